@@ -12,6 +12,9 @@ type AccountService struct {
 
 // Info retrieves user account information.
 func (a *AccountService) Info(ctx context.Context) (AccountInfo, error) {
+	ctx, span := a.client.startSpan(ctx, "AccountService.Info")
+	defer span.End()
+
 	req, err := a.client.NewRequest(ctx, http.MethodGet, "/v2/account/info", nil)
 	if err != nil {
 		return AccountInfo{}, err
@@ -29,6 +32,9 @@ func (a *AccountService) Info(ctx context.Context) (AccountInfo, error) {
 
 // Settings retrieves user preferences.
 func (a *AccountService) Settings(ctx context.Context) (Settings, error) {
+	ctx, span := a.client.startSpan(ctx, "AccountService.Settings")
+	defer span.End()
+
 	req, err := a.client.NewRequest(ctx, http.MethodGet, "/v2/account/settings", nil)
 	if err != nil {
 		return Settings{}, err
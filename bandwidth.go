@@ -0,0 +1,78 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type bandwidthKey struct{}
+
+// ContextWithBandwidth returns a context that limits the transfer rate of the
+// requests made with it to limiter, in bytes per second, in addition to the
+// limits of the Client. Request and response bodies both count against it.
+// Use it to throttle a single upload or download.
+func ContextWithBandwidth(ctx context.Context, limiter *TokenBucket) context.Context {
+	return context.WithValue(ctx, bandwidthKey{}, limiter)
+}
+
+// throttle returns body limited by the given token buckets, which count
+// bytes. Nil buckets are ignored; if all are nil, body is returned as is.
+func throttle(ctx context.Context, body io.ReadCloser, limiters ...*TokenBucket) io.ReadCloser {
+	var active []*TokenBucket
+	for _, l := range limiters {
+		if l != nil {
+			active = append(active, l)
+		}
+	}
+	if len(active) == 0 {
+		return body
+	}
+	return &throttledBody{ReadCloser: body, ctx: ctx, limiters: active}
+}
+
+// throttledBody waits for its limiters after each read.
+type throttledBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	limiters []*TokenBucket
+}
+
+func (b *throttledBody) Read(p []byte) (int, error) {
+	// never read more than a burst at once, so the transfer stays smooth
+	for _, l := range b.limiters {
+		if burst := l.Burst(); len(p) > burst {
+			p = p[:burst]
+		}
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	for _, l := range b.limiters {
+		if werr := waitBytes(b.ctx, l, n); werr != nil {
+			return n, fmt.Errorf("%w", werr)
+		}
+	}
+	return n, err // nolint:wrapcheck
+}
+
+// waitBytes waits until n bytes are allowed by l, in pieces of at most the
+// burst size, which may change at any time.
+func waitBytes(ctx context.Context, l *TokenBucket, n int) error {
+	for n > 0 {
+		piece := n
+		if burst := l.Burst(); piece > burst {
+			piece = burst
+		}
+		err := l.WaitN(ctx, piece)
+		if errors.Is(err, ErrBurstExceeded) {
+			// the burst has shrunk in the meantime
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		n -= piece
+	}
+	return nil
+}
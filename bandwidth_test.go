@@ -0,0 +1,90 @@
+package putio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_UploadBandwidth(t *testing.T) {
+	setup()
+	defer teardown()
+	mux.HandleFunc("/v2/files/upload", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		fmt.Fprintln(w, `{"file":{"id":1}}`)
+	})
+
+	// the bucket starts with 1000 bytes, the rest takes 100 ms per 1000
+	client.UploadBandwidth = NewTokenBucket(10000, 1000)
+	start := time.Now()
+	_, err := client.Files.Upload(context.Background(), strings.NewReader(strings.Repeat("x", 3000)), "x", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("upload took %v, want at least 150ms", elapsed)
+	}
+
+	// limits can be lifted at runtime
+	client.UploadBandwidth.SetRate(0)
+	start = time.Now()
+	_, err = client.Files.Upload(context.Background(), strings.NewReader(strings.Repeat("x", 3000)), "x", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("unlimited upload took %v", elapsed)
+	}
+}
+
+func TestClient_DownloadBandwidth(t *testing.T) {
+	setup()
+	defer teardown()
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Repeat("x", 3000))
+	})
+
+	download := func(ctx context.Context) time.Duration {
+		start := time.Now()
+		req, _ := client.NewRequest(ctx, http.MethodGet, "/download", nil)
+		resp, err := client.Do(req, nil) // nolint:bodyclose
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil || len(b) != 3000 {
+			t.Fatalf("got: %v bytes, %v", len(b), err)
+		}
+		return time.Since(start)
+	}
+
+	if elapsed := download(context.Background()); elapsed > 100*time.Millisecond {
+		t.Errorf("unlimited download took %v", elapsed)
+	}
+
+	client.DownloadBandwidth = NewTokenBucket(10000, 1000)
+	if elapsed := download(context.Background()); elapsed < 150*time.Millisecond {
+		t.Errorf("download took %v, want at least 150ms", elapsed)
+	}
+
+	// a limit of the operation applies on top of the client's
+	client.DownloadBandwidth = nil
+	ctx := ContextWithBandwidth(context.Background(), NewTokenBucket(10000, 1000))
+	if elapsed := download(ctx); elapsed < 150*time.Millisecond {
+		t.Errorf("download took %v, want at least 150ms", elapsed)
+	}
+}
+
+func TestWaitBytes(t *testing.T) {
+	// more than a burst is waited for in pieces
+	l := NewTokenBucket(1e6, 10)
+	err := waitBytes(context.Background(), l, 95)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,156 @@
+package putio
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// crc32Combine returns the IEEE CRC-32 of two concatenated blocks from their
+// checksums and the length of the second block, like zlib's crc32_combine.
+func crc32Combine(crc1, crc2 uint32, len2 int64) uint32 {
+	if len2 <= 0 {
+		return crc1
+	}
+
+	// odd is the operator for one zero bit, even for two
+	var even, odd [32]uint32
+	odd[0] = 0xedb88320 // reversed IEEE polynomial
+	row := uint32(1)
+	for n := 1; n < 32; n++ {
+		odd[n] = row
+		row <<= 1
+	}
+	gf2MatrixSquare(&even, &odd)
+	gf2MatrixSquare(&odd, &even)
+
+	// apply len2 zero bytes to crc1, squaring the operator for each bit of
+	// len2
+	for {
+		gf2MatrixSquare(&even, &odd)
+		if len2&1 != 0 {
+			crc1 = gf2MatrixTimes(&even, crc1)
+		}
+		len2 >>= 1
+		if len2 == 0 {
+			break
+		}
+
+		gf2MatrixSquare(&odd, &even)
+		if len2&1 != 0 {
+			crc1 = gf2MatrixTimes(&odd, crc1)
+		}
+		len2 >>= 1
+		if len2 == 0 {
+			break
+		}
+	}
+	return crc1 ^ crc2
+}
+
+func gf2MatrixTimes(mat *[32]uint32, vec uint32) uint32 {
+	var sum uint32
+	for i := 0; vec != 0; i, vec = i+1, vec>>1 {
+		if vec&1 != 0 {
+			sum ^= mat[i]
+		}
+	}
+	return sum
+}
+
+func gf2MatrixSquare(square, mat *[32]uint32) {
+	for n := 0; n < 32; n++ {
+		square[n] = gf2MatrixTimes(mat, mat[n])
+	}
+}
+
+// parseCRC32 parses a checksum in put.io's format, 8 hex digits.
+func parseCRC32(s string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid CRC32 %q", ErrUnexpected, s)
+	}
+	return uint32(v), nil
+}
+
+// formatCRC32 formats a checksum in put.io's format.
+func formatCRC32(crc uint32) string {
+	return fmt.Sprintf("%08x", crc)
+}
+
+// checkCRC32 compares a checksum computed locally with one reported by the
+// server. An empty remote checksum is not checked.
+func checkCRC32(local uint32, remote string) error {
+	if remote == "" {
+		return nil
+	}
+	want, err := parseCRC32(remote)
+	if err != nil {
+		return err
+	}
+	if local != want {
+		return &ChecksumError{Algorithm: "crc32", Local: formatCRC32(local), Remote: remote}
+	}
+	return nil
+}
+
+// runningCRC32 is the checksum of the first n bytes of a file, which is
+// extended as the file is read in order.
+type runningCRC32 struct {
+	sum uint32
+	n   int64
+}
+
+// catchUp extends the checksum to offset off by reading the missing bytes of
+// r. It leaves r at an unspecified offset.
+func (c *runningCRC32) catchUp(r io.ReadSeeker, off int64) error {
+	if off <= c.n {
+		return nil
+	}
+	_, err := r.Seek(c.n, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	h := crc32.NewIEEE()
+	n, err := io.CopyN(h, r, off-c.n)
+	c.sum = crc32Combine(c.sum, h.Sum32(), n)
+	c.n += n
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+// add extends the checksum by the bytes read through r, which must have
+// started where the checksum ends. Bytes read through r afterwards, such as
+// by a transport still sending a failed request, are ignored.
+func (c *runningCRC32) add(r *crcReader) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done = true
+	c.sum = crc32Combine(c.sum, r.sum, r.n)
+	c.n += r.n
+}
+
+// crcReader computes the checksum of the bytes read through it.
+type crcReader struct {
+	r io.Reader
+
+	mu   sync.Mutex
+	sum  uint32
+	n    int64
+	done bool
+}
+
+func (r *crcReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.mu.Lock()
+	if !r.done {
+		r.sum = crc32.Update(r.sum, crc32.IEEETable, p[:n])
+		r.n += int64(n)
+	}
+	r.mu.Unlock()
+	return n, err // nolint:wrapcheck
+}
@@ -0,0 +1,31 @@
+package putio
+
+import (
+	"hash/crc32"
+	"testing"
+)
+
+func TestCRC32Combine(t *testing.T) {
+	data := []byte(readerContent)
+	want := crc32.ChecksumIEEE(data)
+	for _, split := range []int{0, 1, 7, len(data) - 1, len(data)} {
+		a, b := data[:split], data[split:]
+		got := crc32Combine(crc32.ChecksumIEEE(a), crc32.ChecksumIEEE(b), int64(len(b)))
+		if got != want {
+			t.Errorf("split at %d: got: %08x, want: %08x", split, got, want)
+		}
+	}
+}
+
+func TestParseCRC32(t *testing.T) {
+	crc, err := parseCRC32("0a1b2c3d")
+	if err != nil || crc != 0x0a1b2c3d {
+		t.Errorf("got: %x %v", crc, err)
+	}
+	if formatCRC32(crc) != "0a1b2c3d" {
+		t.Errorf("got: %v, want: 0a1b2c3d", formatCRC32(crc))
+	}
+	if _, err = parseCRC32("xyz"); err == nil {
+		t.Error("invalid checksum is parsed")
+	}
+}
@@ -3,9 +3,12 @@ package putio
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log/slog"
+	"mime"
 	"net/http"
 	"net/url"
 	"strings"
@@ -33,6 +36,14 @@ type Client struct {
 	// Base URL for API requests
 	BaseURL *url.URL
 
+	// UploadURL is the base URL of the upload server used by
+	// FilesService.Upload.
+	UploadURL *url.URL
+
+	// TusURL is the endpoint of the tus server used by UploadService to
+	// create new uploads.
+	TusURL *url.URL
+
 	// User agent for client
 	UserAgent string
 
@@ -42,9 +53,44 @@ type Client struct {
 	// ExtraHeaders are passed to the API server on every request.
 	ExtraHeaders http.Header
 
-	// Timeout for HTTP requests. Zero means no timeout.
+	// Timeout for HTTP requests. Zero means no timeout. When requests are
+	// retried, the timeout applies to each attempt separately. For a body
+	// returned by Do for streaming, the timeout only limits the time until
+	// the response headers arrive and then the time between two reads, so
+	// long downloads are not cut off.
 	Timeout time.Duration
 
+	// RetryPolicy decides which failed requests are retried by Do. Nil
+	// disables retries.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter, if set, is waited on before every request to the API
+	// server, including retries.
+	RateLimiter RateLimiter
+
+	// UploadRateLimiter, if set, is waited on before every request to the
+	// upload server. It is a separate budget from RateLimiter.
+	UploadRateLimiter RateLimiter
+
+	// UploadBandwidth, if set, limits the rate at which request bodies are
+	// sent, in bytes per second. DownloadBandwidth does the same for response
+	// bodies. Their rates may be changed while transfers are running. See
+	// ContextWithBandwidth for limits of a single operation.
+	UploadBandwidth   *TokenBucket
+	DownloadBandwidth *TokenBucket
+
+	// Middleware is the chain every request passes through before it is
+	// sent. The first middleware is the outermost one.
+	Middleware []Middleware
+
+	// Telemetry enables OpenTelemetry tracing and metrics when set.
+	Telemetry *Telemetry
+
+	// Logger receives a structured record for each HTTP request, including
+	// retries and uploads. Authorization headers and tokens in URLs are
+	// redacted. Nil disables logging.
+	Logger *slog.Logger
+
 	// Services used for communicating with the API
 	Account   *AccountService
 	Files     *FilesService
@@ -58,19 +104,24 @@ type Client struct {
 
 // NewClient returns a new Put.io API client, using the htttpClient, which must
 // be a new Oauth2 enabled http.Client. If httpClient is not defined, default
-// HTTP client is used.
+// HTTP client is used. Use New to configure the client with options.
 func NewClient(httpClient *http.Client) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
 	baseURL, _ := url.Parse(defaultBaseURL)
+	uploadURL, _ := url.Parse(defaultUploadURL)
+	tusURL, _ := url.Parse(defaultTusURL)
 	c := &Client{
 		client:       httpClient,
 		BaseURL:      baseURL,
+		UploadURL:    uploadURL,
+		TusURL:       tusURL,
 		UserAgent:    defaultUserAgent,
 		ExtraHeaders: make(http.Header),
 		Timeout:      DefaultClientTimeout,
+		RetryPolicy:  DefaultRetryPolicy(),
 	}
 
 	c.Account = &AccountService{client: c}
@@ -87,6 +138,9 @@ func NewClient(httpClient *http.Client) *Client {
 
 // ValidateToken validates user's OAuth Token.
 func (c *Client) ValidateToken(ctx context.Context) (userID *int64, err error) {
+	ctx, span := c.startSpan(ctx, "Client.ValidateToken")
+	defer span.End()
+
 	req, err := c.NewRequest(ctx, http.MethodGet, "/v2/oauth2/validate", nil)
 	if err != nil {
 		return
@@ -102,7 +156,9 @@ func (c *Client) ValidateToken(ctx context.Context) (userID *int64, err error) {
 }
 
 // NewRequest creates an API request. A relative URL can be provided via
-// relURL, which will be resolved to the BaseURL of the Client.
+// relURL, which will be resolved to the BaseURL of the Client. Absolute URLs
+// are used as is. The special values "$upload$" and "$upload-tus$" stand for
+// UploadURL and TusURL.
 func (c *Client) NewRequest(ctx context.Context, method, relURL string, body io.Reader) (*http.Request, error) {
 	rel, err := url.Parse(relURL)
 	if err != nil {
@@ -113,9 +169,9 @@ func (c *Client) NewRequest(ctx context.Context, method, relURL string, body io.
 	var u *url.URL
 	switch {
 	case relURL == "$upload$":
-		u, _ = url.Parse(defaultUploadURL)
+		u = c.UploadURL
 	case relURL == "$upload-tus$":
-		u, _ = url.Parse(defaultTusURL)
+		u = c.TusURL
 	case strings.HasPrefix(relURL, "http://") || strings.HasPrefix(relURL, "https://"):
 		u = rel
 	default:
@@ -147,40 +203,178 @@ func (c *Client) NewRequest(ctx context.Context, method, relURL string, body io.
 // error if an API error has occurred. Response body is closed at all cases except
 // v is nil. If v is nil, response body is not closed and the body can be used
 // for streaming.
+//
+// Failed requests are retried according to the client's RetryPolicy.
 func (c *Client) Do(r *http.Request, v interface{}) (*http.Response, error) {
-	if c.Timeout > 0 {
-		ctx, cancel := context.WithTimeout(r.Context(), c.Timeout)
-		defer cancel()
-		r = r.WithContext(ctx)
-	}
-
-	resp, err := c.client.Do(r)
-	if err != nil {
-		return nil, fmt.Errorf("%w", err)
-	}
-
-	err = checkResponse(resp)
+	resp, timeout, err := c.send(r)
 	if err != nil {
-		// close the body at all times if there is an http error
-		_ = resp.Body.Close()
+		timeout.stop()
+		c.recordError(r.Context(), err)
 		return resp, err
 	}
 
 	if v == nil {
+		// the timeout must outlive the body being streamed
+		resp.Body = &idleBody{ReadCloser: resp.Body, attempt: timeout}
 		return resp, nil
 	}
+	defer timeout.stop()
 
 	// close the body for all cases from here
 	defer resp.Body.Close()
 
 	err = json.NewDecoder(resp.Body).Decode(v)
 	if err != nil {
+		c.recordError(r.Context(), err)
 		return resp, fmt.Errorf("%w", err)
 	}
 
 	return resp, nil
 }
 
+// attemptTimeout cancels an attempt of a request after Client.Timeout. Unlike
+// a context deadline, it can be pushed back.
+type attemptTimeout struct {
+	timer   *time.Timer
+	timeout time.Duration
+	cancel  context.CancelCauseFunc
+}
+
+// newAttemptTimeout returns a context derived from ctx that is canceled after
+// timeout, or never if timeout is zero.
+func newAttemptTimeout(ctx context.Context, timeout time.Duration) (context.Context, *attemptTimeout) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	t := &attemptTimeout{timeout: timeout, cancel: cancel}
+	if timeout > 0 {
+		t.timer = time.AfterFunc(timeout, func() { cancel(context.DeadlineExceeded) })
+	}
+	return ctx, t
+}
+
+// reset restarts the timeout.
+func (t *attemptTimeout) reset() {
+	if t.timer != nil {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+// stop releases the timeout and cancels the context.
+func (t *attemptTimeout) stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.cancel(nil)
+}
+
+// idleBody is a streamed response body whose timeout restarts on each read.
+// Closing it releases the timeout.
+type idleBody struct {
+	io.ReadCloser
+	attempt *attemptTimeout
+}
+
+func (b *idleBody) Read(p []byte) (int, error) {
+	b.attempt.reset()
+	return b.ReadCloser.Read(p) // nolint:wrapcheck
+}
+
+func (b *idleBody) Close() error {
+	defer b.attempt.stop()
+	return b.ReadCloser.Close() // nolint:wrapcheck
+}
+
+// send sends r until it succeeds or the RetryPolicy gives up. The timeout of
+// the returned last attempt must be stopped after the response body is
+// consumed.
+func (c *Client) send(r *http.Request) (*http.Response, *attemptTimeout, error) {
+	req := r
+	for attempt := 1; ; attempt++ {
+		ctx, timeout := newAttemptTimeout(logAttempt(r.Context(), attempt), c.Timeout)
+
+		resp, err := c.roundTrip(req.WithContext(ctx))
+		if err != nil {
+			resp, err = nil, timeoutError(ctx, err)
+		} else if err = checkResponse(resp); err != nil {
+			// close the body at all times if there is an http error
+			_ = resp.Body.Close()
+		}
+		if err == nil {
+			return resp, timeout, nil
+		}
+		timeout.stop()
+
+		delay, ok := c.RetryPolicy.retry(r, resp, err, attempt)
+		if !ok {
+			return resp, timeout, err
+		}
+		if sleep(r.Context(), delay) != nil {
+			return resp, timeout, err
+		}
+		req, err = rewind(r)
+		if err != nil {
+			return nil, timeout, err
+		}
+	}
+}
+
+// timeoutError wraps the error of an attempt sent with ctx. If the attempt
+// timed out, the error matches context.DeadlineExceeded rather than
+// context.Canceled, so that it is retried.
+func timeoutError(ctx context.Context, err error) error {
+	if !errors.Is(context.Cause(ctx), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w", err)
+	}
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return &url.Error{Op: ue.Op, URL: ue.URL, Err: context.DeadlineExceeded}
+	}
+	return fmt.Errorf("%w", context.DeadlineExceeded)
+}
+
+// roundTrip sends r through the middleware chain without any further
+// processing of the response.
+func (c *Client) roundTrip(r *http.Request) (*http.Response, error) {
+	return c.doer().Do(r) // nolint:wrapcheck
+}
+
+// limitedDo waits for the rate limiter matching the host of r and sends r.
+func (c *Client) limitedDo(r *http.Request) (*http.Response, error) {
+	limiter := c.RateLimiter
+	if c.isUploadHost(r.URL) {
+		limiter = c.UploadRateLimiter
+	}
+	if limiter != nil {
+		err := limiter.Wait(r.Context())
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	ctxLimiter, _ := r.Context().Value(bandwidthKey{}).(*TokenBucket)
+	if r.Body != nil && r.Body != http.NoBody {
+		if body := throttle(r.Context(), r.Body, c.UploadBandwidth, ctxLimiter); body != r.Body {
+			r = r.WithContext(r.Context())
+			r.Body = body
+		}
+	}
+	resp, err := c.client.Do(r)
+	if err != nil {
+		return nil, err // nolint:wrapcheck
+	}
+	resp.Body = throttle(r.Context(), resp.Body, c.DownloadBandwidth, ctxLimiter)
+	return resp, nil
+}
+
+// isUploadHost reports whether u points to the upload or tus server.
+func (c *Client) isUploadHost(u *url.URL) bool {
+	return u.Host == c.UploadURL.Host || u.Host == c.TusURL.Host
+}
+
+// uploadURL resolves path against UploadURL.
+func (c *Client) uploadURL(path string) string {
+	return c.UploadURL.ResolveReference(&url.URL{Path: path}).String()
+}
+
 // checkResponse is the entrypoint to reading the API response. If the response
 // status code is not in success range, it will try to return a structured
 // error.
@@ -191,12 +385,14 @@ func checkResponse(r *http.Response) error {
 
 	// server possibly returns json and more details
 	er := &ErrorResponse{Response: r}
+	er.RetryAfter, _ = retryAfter(r)
 
 	er.Body, er.ParseError = ioutil.ReadAll(r.Body)
 	if er.ParseError != nil {
 		return er
 	}
-	if r.Header.Get("content-type") == "application/json" {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("content-type"))
+	if mediaType == "application/json" {
 		er.ParseError = json.Unmarshal(er.Body, er)
 		if er.ParseError != nil {
 			return er
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -19,8 +20,14 @@ func setup() {
 	server = httptest.NewServer(mux)
 
 	client = NewClient(nil)
-	url, _ := url.Parse(server.URL)
-	client.BaseURL = url
+	u, _ := url.Parse(server.URL)
+	client.BaseURL = u
+
+	// Upload endpoints are served by the same server under a different host
+	// name, so that they can be told apart from API requests.
+	uploadURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	client.UploadURL, _ = url.Parse(uploadURL)
+	client.TusURL, _ = url.Parse(uploadURL + "/files/")
 }
 
 func teardown() {
@@ -15,6 +15,9 @@ type ConfigService struct {
 
 // GetAll all fills config.
 func (f *ConfigService) GetAll(ctx context.Context, config interface{}) error {
+	ctx, span := f.client.startSpan(ctx, "ConfigService.GetAll")
+	defer span.End()
+
 	req, err := f.client.NewRequest(ctx, http.MethodGet, "/v2/config", nil)
 	if err != nil {
 		return fmt.Errorf("%w", err)
@@ -31,6 +34,9 @@ func (f *ConfigService) GetAll(ctx context.Context, config interface{}) error {
 
 // Get fetches config item via given key.
 func (f *ConfigService) Get(ctx context.Context, key string, value interface{}) (found bool, err error) {
+	ctx, span := f.client.startSpan(ctx, "ConfigService.Get")
+	defer span.End()
+
 	req, err := f.client.NewRequest(ctx, http.MethodGet, "/v2/config/"+key, nil)
 	if err != nil {
 		return false, err
@@ -50,6 +56,9 @@ func (f *ConfigService) Get(ctx context.Context, key string, value interface{})
 
 // SetAll updates all config items.
 func (f *ConfigService) SetAll(ctx context.Context, config interface{}) error {
+	ctx, span := f.client.startSpan(ctx, "ConfigService.SetAll")
+	defer span.End()
+
 	b, err := json.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("%w", err)
@@ -74,6 +83,9 @@ func (f *ConfigService) SetAll(ctx context.Context, config interface{}) error {
 
 // Set updates given config key's value.
 func (f *ConfigService) Set(ctx context.Context, key string, value interface{}) error {
+	ctx, span := f.client.startSpan(ctx, "ConfigService.Set")
+	defer span.End()
+
 	b, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("%w", err)
@@ -98,6 +110,9 @@ func (f *ConfigService) Set(ctx context.Context, key string, value interface{})
 
 // Del destroys config item via given key.
 func (f *ConfigService) Del(ctx context.Context, key string) error {
+	ctx, span := f.client.startSpan(ctx, "ConfigService.Del")
+	defer span.End()
+
 	req, err := f.client.NewRequest(ctx, http.MethodDelete, "/v2/config/"+key, nil)
 	if err != nil {
 		return fmt.Errorf("%w", err)
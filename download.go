@@ -0,0 +1,340 @@
+package putio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	// defaultDownloadWorkers is used when DownloadOptions.Workers is zero.
+	defaultDownloadWorkers = 4
+
+	// defaultSegmentSize is used when DownloadOptions.SegmentSize is zero.
+	defaultSegmentSize = 16 << 20
+)
+
+// DownloadOptions configure FilesService.Download.
+type DownloadOptions struct {
+	// Workers is the number of segments downloaded at the same time. The
+	// default is 4.
+	Workers int
+
+	// SegmentSize is the size of the ranges the file is split into. The
+	// default is 16 MiB.
+	SegmentSize int64
+
+	// StateFile is the path of a file that records the finished segments.
+	// If it exists and belongs to the same file, the download resumes where
+	// it stopped; dst must then hold the data written before. If dst is an
+	// io.ReaderAt, such as an *os.File, the recorded segments are checked
+	// against its contents and those that differ are downloaded again. The
+	// state file is removed once the download succeeds. Empty disables
+	// resuming.
+	StateFile string
+
+	// Progress, if not nil, receives progress reports. Bytes of resumed
+	// segments count as done from the start.
+	Progress ProgressFunc
+
+	// UseTunnel downloads through put.io's tunnel, see FilesService.URL.
+	UseTunnel bool
+}
+
+// downloadState is the content of DownloadOptions.StateFile.
+type downloadState struct {
+	FileID      int64  `json:"file_id"`
+	Size        int64  `json:"size"`
+	CRC32       string `json:"crc32"`
+	SegmentSize int64  `json:"segment_size"`
+	// Segments maps finished segments to their checksums.
+	Segments map[int]uint32 `json:"segments"`
+}
+
+// Download downloads the file with the given ID to dst, fetching segments of
+// the file in parallel. Once all segments are written, the checksum of the
+// data is compared with File.CRC32 and a *ChecksumError is returned if they
+// differ. Nil opts is equivalent to the zero DownloadOptions.
+func (f *FilesService) Download(ctx context.Context, id int64, dst io.WriterAt, opts *DownloadOptions) error {
+	ctx, span := f.client.startSpan(ctx, "FilesService.Download", attrFileID.Int64(id))
+	defer span.End()
+
+	d := &downloader{dst: dst}
+	if opts != nil {
+		d.opts = *opts
+	}
+	if d.opts.Workers <= 0 {
+		d.opts.Workers = defaultDownloadWorkers
+	}
+	if d.opts.SegmentSize <= 0 {
+		d.opts.SegmentSize = defaultSegmentSize
+	}
+
+	file, err := f.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	d.reader, err = f.openFile(ctx, file, &ReaderOptions{UseTunnel: d.opts.UseTunnel})
+	if err != nil {
+		return err
+	}
+
+	d.loadState(file)
+	err = d.run(ctx, cancel)
+	if err != nil {
+		return err
+	}
+	return d.verify()
+}
+
+type downloader struct {
+	opts   DownloadOptions
+	dst    io.WriterAt
+	reader *FileReader
+
+	// mu guards state
+	mu       sync.Mutex
+	state    downloadState
+	progress *progressTracker
+}
+
+// loadState reads the state file, keeping the finished segments if it
+// belongs to file and, if dst can be read, still holds them.
+func (d *downloader) loadState(file File) {
+	d.state = downloadState{
+		FileID:      file.ID,
+		Size:        file.Size,
+		CRC32:       file.CRC32,
+		SegmentSize: d.opts.SegmentSize,
+		Segments:    make(map[int]uint32),
+	}
+	if d.opts.StateFile == "" {
+		return
+	}
+
+	b, err := os.ReadFile(d.opts.StateFile)
+	if err != nil {
+		return
+	}
+	var saved downloadState
+	err = json.Unmarshal(b, &saved)
+	if err != nil || saved.FileID != file.ID || saved.Size != file.Size ||
+		saved.CRC32 != file.CRC32 || saved.SegmentSize != d.opts.SegmentSize || saved.Segments == nil {
+		return
+	}
+	d.state.Segments = saved.Segments
+
+	// dst may be truncated, replaced or missing writes lost in a crash
+	ra, ok := d.dst.(io.ReaderAt)
+	if !ok {
+		return
+	}
+	for seg, sum := range d.state.Segments {
+		if seg < 0 || seg >= d.segments() {
+			delete(d.state.Segments, seg)
+			continue
+		}
+		n := d.segmentLen(seg)
+		h := crc32.NewIEEE()
+		m, err := io.Copy(h, io.NewSectionReader(ra, int64(seg)*d.opts.SegmentSize, n))
+		if err != nil || m != n || h.Sum32() != sum {
+			delete(d.state.Segments, seg)
+		}
+	}
+}
+
+// saveState writes the state file. It must be called with mu held.
+func (d *downloader) saveState() error {
+	if d.opts.StateFile == "" {
+		return nil
+	}
+	// the segments recorded must survive a crash
+	if f, ok := d.dst.(*os.File); ok {
+		err := f.Sync()
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+	b, err := json.Marshal(d.state)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return writeFileAtomic(d.opts.StateFile, b)
+}
+
+func (d *downloader) removeState() {
+	if d.opts.StateFile != "" {
+		_ = os.Remove(d.opts.StateFile)
+	}
+}
+
+func (d *downloader) segments() int {
+	return int((d.state.Size + d.opts.SegmentSize - 1) / d.opts.SegmentSize)
+}
+
+func (d *downloader) segmentLen(seg int) int64 {
+	off := int64(seg) * d.opts.SegmentSize
+	if rest := d.state.Size - off; rest < d.opts.SegmentSize {
+		return rest
+	}
+	return d.opts.SegmentSize
+}
+
+// run downloads the missing segments. The first error cancels the other
+// workers.
+func (d *downloader) run(ctx context.Context, cancel context.CancelFunc) error {
+	var done int64
+	for seg := range d.state.Segments {
+		done += d.segmentLen(seg)
+	}
+	d.progress = newProgressTracker(d.opts.Progress, d.state.Size, done)
+	d.progress.add(0)
+	defer d.progress.finish()
+
+	var pending []int
+	for seg := 0; seg < d.segments(); seg++ {
+		if _, ok := d.state.Segments[seg]; !ok {
+			pending = append(pending, seg)
+		}
+	}
+
+	segments := make(chan int)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < d.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seg := range segments {
+				err := d.fetch(ctx, seg)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, seg := range pending {
+		select {
+		case segments <- seg:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(segments)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+// fetch downloads a segment and records its checksum.
+func (d *downloader) fetch(ctx context.Context, seg int) error {
+	off := int64(seg) * d.opts.SegmentSize
+	n := d.segmentLen(seg)
+	h := crc32.NewIEEE()
+	w := &segmentWriter{d: d, dst: d.dst, off: off}
+	mw := io.MultiWriter(w, h)
+
+	var written int64
+	for attempt := 1; written < n; attempt++ {
+		body, err := d.reader.open(off+written, n-written)
+		if err == nil {
+			var m int64
+			m, err = io.Copy(mw, io.LimitReader(body, n-written))
+			_ = body.Close()
+			written += m
+			if m > 0 {
+				attempt = 0
+			}
+			if err == nil && written < n {
+				err = io.ErrUnexpectedEOF
+			}
+		}
+		if w.err != nil {
+			return w.err
+		}
+		if err != nil {
+			err = d.reader.backoff(err, attempt)
+			if err != nil {
+				return err
+			}
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w", ctx.Err())
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.state.Segments[seg] = h.Sum32()
+	return d.saveState()
+}
+
+// verify compares the checksum of the segments with the one of the file.
+func (d *downloader) verify() error {
+	if d.state.CRC32 == "" {
+		d.removeState()
+		return nil
+	}
+	var crc uint32
+	for seg := 0; seg < d.segments(); seg++ {
+		crc = crc32Combine(crc, d.state.Segments[seg], d.segmentLen(seg))
+	}
+	// the segments are useless for another attempt
+	d.removeState()
+	return checkCRC32(crc, d.state.CRC32)
+}
+
+// segmentWriter writes a segment to dst at increasing offsets. It keeps the
+// error of dst apart from the errors of the connection.
+type segmentWriter struct {
+	d   *downloader
+	dst io.WriterAt
+	off int64
+	err error
+}
+
+func (w *segmentWriter) Write(p []byte) (int, error) {
+	n, err := w.dst.WriteAt(p, w.off)
+	w.off += int64(n)
+	w.d.progress.add(int64(n))
+	if err != nil {
+		w.err = fmt.Errorf("%w", err)
+		return n, w.err
+	}
+	return n, nil
+}
+
+// writeFileAtomic replaces the file at path with b at once, so an interrupted
+// write never leaves a corrupt file behind.
+func writeFileAtomic(path string, b []byte) error {
+	tmp := path + ".tmp"
+	err := os.WriteFile(tmp, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package putio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// memWriterAt is an io.WriterAt over a byte slice of fixed size.
+type memWriterAt []byte
+
+func (m memWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	return copy(m[off:], p), nil
+}
+
+func TestFiles_Download(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+	ranges := setupReader(t, 10)
+
+	var mu sync.Mutex
+	var reported []int64
+	dst := make(memWriterAt, len(readerContent))
+	err := client.Files.Download(context.Background(), 1, dst, &DownloadOptions{
+		Workers:     3,
+		SegmentSize: 5,
+		Progress: func(p Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+			if p.Total != int64(len(readerContent)) {
+				t.Errorf("got total: %v", p.Total)
+			}
+			reported = append(reported, p.Done)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(dst) != readerContent {
+		t.Errorf("got: %q, want: %q", dst, readerContent)
+	}
+	if !sort.SliceIsSorted(reported, func(i, j int) bool { return reported[i] < reported[j] }) ||
+		reported[len(reported)-1] != int64(len(readerContent)) {
+		t.Errorf("got progress: %v", reported)
+	}
+	// 8 segments and the reconnect of the dropped one
+	if got := ranges(); len(got) != 9 {
+		t.Errorf("got: %v, want: 9 requests", got)
+	}
+}
+
+func TestFiles_Download_resume(t *testing.T) {
+	setup()
+	defer teardown()
+	ranges := setupReader(t)
+
+	// the first 3 of 4 segments are done
+	stateFile := filepath.Join(t.TempDir(), "download.json")
+	state := downloadState{
+		FileID:      1,
+		Size:        int64(len(readerContent)),
+		CRC32:       formatCRC32(crc32.ChecksumIEEE([]byte(readerContent))),
+		SegmentSize: 10,
+		Segments:    make(map[int]uint32),
+	}
+	dst := make(memWriterAt, len(readerContent))
+	for seg := 0; seg < 3; seg++ {
+		part := readerContent[seg*10 : seg*10+10]
+		copy(dst[seg*10:], part)
+		state.Segments[seg] = crc32.ChecksumIEEE([]byte(part))
+	}
+	b, _ := json.Marshal(state)
+	err := os.WriteFile(stateFile, b, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var first int64 = -1
+	err = client.Files.Download(context.Background(), 1, dst, &DownloadOptions{
+		SegmentSize: 10,
+		StateFile:   stateFile,
+		Progress: func(p Progress) {
+			if first < 0 {
+				first = p.Done
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(dst) != readerContent {
+		t.Errorf("got: %q, want: %q", dst, readerContent)
+	}
+	if got := ranges(); len(got) != 1 || got[0] != "bytes=30-35" {
+		t.Errorf("got: %v, want: [bytes=30-35]", got)
+	}
+	if first != 30 {
+		t.Errorf("got first progress: %v, want: 30", first)
+	}
+	if _, err = os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("state file is not removed: %v", err)
+	}
+}
+
+func TestFiles_Download_resumeTruncated(t *testing.T) {
+	setup()
+	defer teardown()
+	ranges := setupReader(t)
+
+	// the state records 3 segments, but only the first one is in dst
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "download.json")
+	state := downloadState{
+		FileID:      1,
+		Size:        int64(len(readerContent)),
+		CRC32:       formatCRC32(crc32.ChecksumIEEE([]byte(readerContent))),
+		SegmentSize: 10,
+		Segments:    make(map[int]uint32),
+	}
+	for seg := 0; seg < 3; seg++ {
+		state.Segments[seg] = crc32.ChecksumIEEE([]byte(readerContent[seg*10 : seg*10+10]))
+	}
+	b, _ := json.Marshal(state)
+	err := os.WriteFile(stateFile, b, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst, err := os.Create(filepath.Join(dir, "download"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	_, err = dst.WriteString(readerContent[:10])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.Files.Download(context.Background(), 1, dst, &DownloadOptions{SegmentSize: 10, StateFile: stateFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(dst.Name())
+	if string(got) != readerContent {
+		t.Errorf("got: %q, want: %q", got, readerContent)
+	}
+	requests := ranges()
+	sort.Strings(requests)
+	want := []string{"bytes=10-19", "bytes=20-29", "bytes=30-35"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("got: %v, want: %v", requests, want)
+	}
+}
+
+func TestFiles_Download_checksumMismatch(t *testing.T) {
+	setup()
+	defer teardown()
+	setupReader(t)
+
+	// a corrupt segment left by an earlier attempt
+	stateFile := filepath.Join(t.TempDir(), "download.json")
+	b, _ := json.Marshal(downloadState{
+		FileID:      1,
+		Size:        int64(len(readerContent)),
+		CRC32:       formatCRC32(crc32.ChecksumIEEE([]byte(readerContent))),
+		SegmentSize: 10,
+		Segments:    map[int]uint32{0: 0xdeadbeef},
+	})
+	err := os.WriteFile(stateFile, b, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := make(memWriterAt, len(readerContent))
+	err = client.Files.Download(context.Background(), 1, dst, &DownloadOptions{SegmentSize: 10, StateFile: stateFile})
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("got: %v, want: %v", err, ErrChecksumMismatch)
+	}
+	if _, err = os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("state file is not removed: %v", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // Sentinel errors.
@@ -19,9 +21,54 @@ var (
 	ErrNoFileIsGiven            = errors.New("no files given")
 	ErrEmptyUserName            = errors.New("empty username")
 	ErrEmptyURL                 = errors.New("empty URL")
+	ErrInvalidURL               = errors.New("invalid URL")
+	ErrNotDir                   = errors.New("not a directory")
+	ErrIsDir                    = errors.New("is a directory")
+	ErrRangeNotSupported        = errors.New("range requests are not supported")
+	ErrChecksumMismatch         = errors.New("checksum mismatch")
+	ErrTransferFailed           = errors.New("transfer failed")
+	ErrUploadStalled            = errors.New("upload stalled")
+	ErrBurstExceeded            = errors.New("burst size exceeded")
 	ErrUnexpected               = errors.New("unexpected error")
 )
 
+// Errors returned by the API. An *ErrorResponse matches one of them with
+// errors.Is, based on put.io's error_type or the HTTP status code.
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrRateLimited   = errors.New("rate limited")
+	ErrValidation    = errors.New("validation failed")
+	ErrServerError   = errors.New("server error")
+)
+
+// errorTypes maps put.io error types, normalized by normalizeErrorType, to
+// API errors.
+var errorTypes = map[string]error{
+	"NOTFOUND":          ErrNotFound,
+	"FILENOTFOUND":      ErrNotFound,
+	"TRANSFERNOTFOUND":  ErrNotFound,
+	"UNAUTHORIZED":      ErrUnauthorized,
+	"INVALIDGRANT":      ErrUnauthorized,
+	"INVALIDTOKEN":      ErrUnauthorized,
+	"FORBIDDEN":         ErrForbidden,
+	"QUOTAEXCEEDED":     ErrQuotaExceeded,
+	"NOTENOUGHSPACE":    ErrQuotaExceeded,
+	"INSUFFICIENTSPACE": ErrQuotaExceeded,
+	"DISKFULL":          ErrQuotaExceeded,
+	"RATELIMITED":       ErrRateLimited,
+	"TOOMANYREQUESTS":   ErrRateLimited,
+	"VALIDATIONERROR":   ErrValidation,
+	"INVALIDARGUMENT":   ErrValidation,
+	"INVALIDREQUEST":    ErrValidation,
+	"BADREQUEST":        ErrValidation,
+}
+
+// maxErrorBodyLen is the number of body bytes included in error messages.
+const maxErrorBodyLen = 250
+
 // ErrorResponse reports the error caused by an API request.
 type ErrorResponse struct {
 	// Original http.Response
@@ -36,6 +83,49 @@ type ErrorResponse struct {
 	// These fileds are parsed from response if JSON.
 	Message string `json:"error_message"`
 	Type    string `json:"error_type"`
+
+	// Extra holds additional details sent with some errors, such as the
+	// fields that failed validation.
+	Extra map[string]interface{} `json:"extra"`
+
+	// RetryAfter is the delay requested by the server in the Retry-After
+	// header, usually along with a 429 or 503 status code.
+	RetryAfter time.Duration `json:"-"`
+}
+
+// Kind returns the API error this response corresponds to, such as
+// ErrNotFound or ErrRateLimited. It returns nil if the error cannot be
+// classified.
+func (e *ErrorResponse) Kind() error {
+	if err, ok := errorTypes[normalizeErrorType(e.Type)]; ok {
+		return err
+	}
+	if e.Response == nil {
+		return nil
+	}
+	switch code := e.Response.StatusCode; {
+	case code == http.StatusBadRequest, code == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case code == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case code == http.StatusForbidden:
+		return ErrForbidden
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code == http.StatusRequestEntityTooLarge, code == http.StatusInsufficientStorage:
+		return ErrQuotaExceeded
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code >= 500:
+		return ErrServerError
+	}
+	return nil
+}
+
+// Is reports whether target is the API error returned by Kind.
+func (e *ErrorResponse) Is(target error) bool {
+	kind := e.Kind()
+	return kind != nil && kind == target
 }
 
 // nolint:goerr113
@@ -45,7 +135,7 @@ func (e *ErrorResponse) Error() string {
 			"cannot parse response. code:%d error:%w body:%q",
 			e.Response.StatusCode,
 			e.ParseError,
-			string(e.Body[:250]),
+			string(truncate(e.Body, maxErrorBodyLen)),
 		).Error()
 	}
 	return fmt.Sprintf(
@@ -57,3 +147,71 @@ func (e *ErrorResponse) Error() string {
 		e.Response.Request.URL,
 	)
 }
+
+// ChecksumError reports data that differs between the client and the server.
+// It matches ErrChecksumMismatch.
+type ChecksumError struct {
+	// Algorithm is the checksum algorithm, such as "crc32" for put.io's
+	// checksum of a whole file or "sha1" for a tus chunk.
+	Algorithm string
+
+	// Offset is the offset of the rejected chunk of an upload.
+	Offset int64
+
+	// Local is the checksum computed by the client. Remote is the one
+	// computed by the server; it is empty if the server only reported that
+	// the checksums differ.
+	Local  string
+	Remote string
+}
+
+func (e *ChecksumError) Error() string {
+	if e.Remote == "" {
+		return fmt.Sprintf("%v: %s %s rejected at offset %d", ErrChecksumMismatch, e.Algorithm, e.Local, e.Offset)
+	}
+	return fmt.Sprintf("%v: %s local %s, remote %s", ErrChecksumMismatch, e.Algorithm, e.Local, e.Remote)
+}
+
+// Is reports whether target is ErrChecksumMismatch.
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// TransferError reports a transfer that ended with an error. It matches
+// ErrTransferFailed.
+type TransferError struct {
+	// Transfer is the last snapshot of the failed transfer.
+	Transfer Transfer
+	// Retries is the number of times the transfer was retried.
+	Retries int
+}
+
+func (e *TransferError) Error() string {
+	return fmt.Sprintf("%v: id:%d message:%q", ErrTransferFailed, e.Transfer.ID, e.Transfer.ErrorMessage)
+}
+
+// Is reports whether target is ErrTransferFailed.
+func (e *TransferError) Is(target error) bool {
+	return target == ErrTransferFailed
+}
+
+// normalizeErrorType uppercases t and drops everything but letters and digits,
+// so "NotFound", "NOT_FOUND" and "not-found" are equal.
+func normalizeErrorType(t string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return -1
+	}, t)
+}
+
+func truncate(b []byte, n int) []byte {
+	if len(b) > n {
+		return b[:n]
+	}
+	return b
+}
@@ -0,0 +1,104 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorResponse_Is(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = nil
+
+	tests := []struct {
+		status    int
+		errorType string
+		want      error
+	}{
+		{http.StatusNotFound, "", ErrNotFound},
+		{http.StatusBadRequest, "NotFound", ErrNotFound},
+		{http.StatusUnauthorized, "invalid_grant", ErrUnauthorized},
+		{http.StatusForbidden, "", ErrForbidden},
+		{http.StatusBadRequest, "NOT_ENOUGH_SPACE", ErrQuotaExceeded},
+		{http.StatusTooManyRequests, "", ErrRateLimited},
+		{http.StatusBadRequest, "", ErrValidation},
+		{http.StatusServiceUnavailable, "", ErrServerError},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		path := fmt.Sprintf("/v2/error/%d/%s", tt.status, tt.errorType)
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(tt.status)
+			fmt.Fprintf(w, `{"error_type":%q,"error_message":"oops","status":"ERROR"}`, tt.errorType)
+		})
+
+		req, _ := client.NewRequest(context.Background(), http.MethodGet, path, nil)
+		_, err := client.Do(req, nil) // nolint:bodyclose
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%d %q: got: %v, want: %v", tt.status, tt.errorType, err, tt.want)
+		}
+
+		var er *ErrorResponse
+		if !errors.As(err, &er) || er.Type != tt.errorType || er.Message != "oops" {
+			t.Errorf("%d %q: raw details are not available: %#v", tt.status, tt.errorType, err)
+		}
+	}
+}
+
+func TestErrorResponse_extraAndRetryAfter(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = nil
+
+	mux.HandleFunc("/v2/error", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintln(w, `{"error_type":"RATE_LIMITED","error_message":"slow down","extra":{"limit":10}}`)
+	})
+
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/v2/error", nil)
+	_, err := client.Do(req, nil) // nolint:bodyclose
+
+	var er *ErrorResponse
+	if !errors.As(err, &er) {
+		t.Fatalf("got: %T, want: *ErrorResponse", err)
+	}
+	if er.RetryAfter != 30*time.Second {
+		t.Errorf("got: %v, want: 30s", er.RetryAfter)
+	}
+	if er.Extra["limit"] != float64(10) {
+		t.Errorf("got: %v, want: 10", er.Extra["limit"])
+	}
+}
+
+func TestErrorResponse_Error_shortBody(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = nil
+
+	mux.HandleFunc("/v2/error", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{oops`)
+	})
+
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/v2/error", nil)
+	_, err := client.Do(req, nil) // nolint:bodyclose
+	if err == nil {
+		t.Fatal("must not return nil")
+	}
+	if msg := err.Error(); !strings.Contains(msg, `{oops`) {
+		t.Errorf("got: %q, want body in message", msg)
+	}
+	if !errors.Is(err, ErrServerError) {
+		t.Errorf("got: %v, want: %v", err, ErrServerError)
+	}
+}
@@ -15,6 +15,9 @@ type EventsService struct {
 
 // List gets list of dashboard events. It includes downloads and share events.
 func (e *EventsService) List(ctx context.Context) ([]Event, error) {
+	ctx, span := e.client.startSpan(ctx, "EventsService.List")
+	defer span.End()
+
 	req, err := e.client.NewRequest(ctx, http.MethodGet, "/v2/events/list", nil)
 	if err != nil {
 		return nil, err
@@ -32,6 +35,9 @@ func (e *EventsService) List(ctx context.Context) ([]Event, error) {
 
 // Delete Clears all dashboard events.
 func (e *EventsService) Delete(ctx context.Context) error {
+	ctx, span := e.client.startSpan(ctx, "EventsService.Delete")
+	defer span.End()
+
 	req, err := e.client.NewRequest(ctx, http.MethodPost, "/v2/events/delete", nil)
 	if err != nil {
 		return err
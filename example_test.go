@@ -4,8 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
-	"github.com/putdotio/go-putio"
+	"github.com/systemmonkey42/go-putio"
 	"golang.org/x/oauth2"
 )
 
@@ -26,3 +27,21 @@ func Example() {
 
 	fmt.Printf("Name of root folder is: %s\n", root.Name)
 }
+
+func ExampleNew() {
+	client, err := putio.New(
+		putio.WithToken(token),
+		putio.WithTimeout(time.Minute),
+		putio.WithRateLimiter(putio.NewTokenBucket(5, 10)),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	root, err := client.Files.Get(context.TODO(), 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Name of root folder is: %s\n", root.Name)
+}
@@ -16,11 +16,18 @@ import (
 // such as listing, searching, creating new ones, or just fetching a single
 // file.
 type FilesService struct {
+	// PathCache, if set, caches the paths resolved by LookupPath, MkdirAll
+	// and PathOf.
+	PathCache *PathCache
+
 	client *Client
 }
 
 // Get fetches file metadata for given file ID.
 func (f *FilesService) Get(ctx context.Context, id int64) (File, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.Get", attrFileID.Int64(id))
+	defer span.End()
+
 	req, err := f.client.NewRequest(ctx, http.MethodGet, "/v2/files/"+itoa(id), nil)
 	if err != nil {
 		return File{}, err
@@ -36,43 +43,34 @@ func (f *FilesService) Get(ctx context.Context, id int64) (File, error) {
 	return r.File, nil
 }
 
-// List fetches children for given directory ID.
+// List fetches children for given directory ID. It follows every cursor and
+// returns the whole folder at once; use Iterate for large folders.
 func (f *FilesService) List(ctx context.Context, id int64) (children []File, parent File, err error) {
-	req, err := f.client.NewRequest(ctx, http.MethodGet, "/v2/files/list?parent_id="+itoa(id)+"&per_page=1000", nil)
-	if err != nil {
-		return
-	}
-	var r struct {
-		Files  []File `json:"files"`
-		Parent File   `json:"parent"`
-		Cursor string `json:"cursor"`
-	}
-	_, err = f.client.Do(req, &r) // nolint:bodyclose
-	if err != nil {
-		return
-	}
-	children = append(children, r.Files...)
-	parent = r.Parent
-	for r.Cursor != "" {
-		body := strings.NewReader(`{"cursor": "` + r.Cursor + `"}`)
-		req, err = f.client.NewRequest(ctx, http.MethodPost, "/v2/files/list/continue", body)
-		if err != nil {
-			return
-		}
-		req.Header.Set("content-type", "application/json")
-		r.Files = nil
-		r.Cursor = ""
-		_, err = f.client.Do(req, &r) // nolint:bodyclose
-		if err != nil {
-			return
-		}
-		children = append(children, r.Files...)
+	return f.ListWithOptions(ctx, id, nil)
+}
+
+// ListWithOptions is like List, but lets the server sort and filter the
+// listing and fill the optional File fields selected by opts.
+func (f *FilesService) ListWithOptions(
+	ctx context.Context,
+	id int64,
+	opts *ListOptions,
+) (children []File, parent File, err error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.List", attrFileID.Int64(id))
+	defer span.End()
+
+	it := f.Iterate(ctx, id, opts)
+	for it.Next() {
+		children = append(children, it.File())
 	}
-	return
+	return children, it.Parent(), it.Err()
 }
 
 // URL returns a URL of the file for downloading or streaming.
 func (f *FilesService) URL(ctx context.Context, id int64, useTunnel bool) (string, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.URL", attrFileID.Int64(id))
+	defer span.End()
+
 	notunnel := "notunnel=1"
 	if useTunnel {
 		notunnel = "notunnel=0"
@@ -97,6 +95,9 @@ func (f *FilesService) URL(ctx context.Context, id int64, useTunnel bool) (strin
 
 // CreateFolder creates a new folder under parent.
 func (f *FilesService) CreateFolder(ctx context.Context, name string, parent int64) (File, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.CreateFolder", attrParentID.Int64(parent))
+	defer span.End()
+
 	if name == "" {
 		return File{}, ErrEmptyFolderName
 	}
@@ -124,6 +125,9 @@ func (f *FilesService) CreateFolder(ctx context.Context, name string, parent int
 
 // Delete deletes given files.
 func (f *FilesService) Delete(ctx context.Context, files ...int64) error {
+	ctx, span := f.client.startSpan(ctx, "FilesService.Delete", attrFileIDs.Int64Slice(files))
+	defer span.End()
+
 	if len(files) == 0 {
 		return ErrNoFileIDIsGiven
 	}
@@ -146,11 +150,15 @@ func (f *FilesService) Delete(ctx context.Context, files ...int64) error {
 	if err != nil {
 		return err
 	}
+	f.invalidate(files...)
 	return nil
 }
 
 // Rename change the name of the file to newname.
 func (f *FilesService) Rename(ctx context.Context, id int64, newname string) error {
+	ctx, span := f.client.startSpan(ctx, "FilesService.Rename", attrFileID.Int64(id))
+	defer span.End()
+
 	if newname == "" {
 		return ErrNewFilenameCanNotBeEmpty
 	}
@@ -169,12 +177,16 @@ func (f *FilesService) Rename(ctx context.Context, id int64, newname string) err
 	if err != nil {
 		return err
 	}
+	f.invalidate(id)
 
 	return nil
 }
 
 // Move moves files to the given destination.
 func (f *FilesService) Move(ctx context.Context, parent int64, files ...int64) error {
+	ctx, span := f.client.startSpan(ctx, "FilesService.Move", attrParentID.Int64(parent), attrFileIDs.Int64Slice(files))
+	defer span.End()
+
 	if len(files) == 0 {
 		return ErrNoFileIsGiven
 	}
@@ -198,6 +210,7 @@ func (f *FilesService) Move(ctx context.Context, parent int64, files ...int64) e
 	if err != nil {
 		return err
 	}
+	f.invalidate(files...)
 	return nil
 }
 
@@ -213,6 +226,9 @@ func (f *FilesService) Move(ctx context.Context, parent int64, files ...int64) e
 // This method reads the file contents into the memory, so it should only be used for small files.
 // Use UploadService for larger files.
 func (f *FilesService) Upload(ctx context.Context, r io.Reader, filename string, parent int64) (Upload, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.Upload", attrParentID.Int64(parent))
+	defer span.End()
+
 	if filename == "" {
 		return Upload{}, ErrFilenameCanNotBeEmpty
 	}
@@ -243,7 +259,7 @@ func (f *FilesService) Upload(ctx context.Context, r io.Reader, filename string,
 		return Upload{}, fmt.Errorf("%w", err)
 	}
 
-	req, err := f.client.NewRequest(ctx, http.MethodPost, "/v2/files/upload", &buf)
+	req, err := f.client.NewRequest(ctx, http.MethodPost, f.client.uploadURL("/v2/files/upload"), &buf)
 	if err != nil {
 		return Upload{}, fmt.Errorf("%w", err)
 	}
@@ -263,6 +279,9 @@ func (f *FilesService) Upload(ctx context.Context, r io.Reader, filename string,
 // results at a time. The URL for the next 50 results are in Next field.  If
 // page is -1, all results are returned.
 func (f *FilesService) Search(ctx context.Context, query string, page int64) (Search, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.Search")
+	defer span.End()
+
 	if page == 0 || page < -1 {
 		return Search{}, ErrInvalidPageNumber
 	}
@@ -327,6 +346,9 @@ func (f *FilesService) sharedWith(ctx context.Context, id int64) ([]share, error
 // Subtitles lists available subtitles for the given file for user's preferred
 // subtitle language.
 func (f *FilesService) Subtitles(ctx context.Context, id int64) ([]Subtitle, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.Subtitles", attrFileID.Int64(id))
+	defer span.End()
+
 	req, err := f.client.NewRequest(ctx, http.MethodGet, "/v2/files/"+itoa(id)+"/subtitles", nil)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
@@ -355,6 +377,9 @@ func (f *FilesService) DownloadSubtitle(
 	id int64,
 	key string,
 ) (io.ReadCloser, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.DownloadSubtitle", attrFileID.Int64(id))
+	defer span.End()
+
 	if key == "" {
 		key = "default"
 	}
@@ -374,6 +399,9 @@ func (f *FilesService) DownloadSubtitle(
 // HLSPlaylist serves a HLS playlist for a video file. Use “all” as
 // subtitleKey to get available subtitles for user’s preferred languages.
 func (f *FilesService) HLSPlaylist(ctx context.Context, id int64, subtitleKey string) (io.ReadCloser, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.HLSPlaylist", attrFileID.Int64(id))
+	defer span.End()
+
 	if subtitleKey == "" {
 		return nil, ErrEmptySubtitleKey
 	}
@@ -398,6 +426,9 @@ func (f *FilesService) HLSPlaylist(ctx context.Context, id int64, subtitleKey st
 
 // SetVideoPosition sets default video position for a video file.
 func (f *FilesService) SetVideoPosition(ctx context.Context, id int64, t int) error {
+	ctx, span := f.client.startSpan(ctx, "FilesService.SetVideoPosition", attrFileID.Int64(id))
+	defer span.End()
+
 	if t < 0 {
 		return ErrNegativeTimeValue
 	}
@@ -426,6 +457,9 @@ func (f *FilesService) SetVideoPosition(ctx context.Context, id int64, t int) er
 
 // DeleteVideoPosition deletes video position for a video file.
 func (f *FilesService) DeleteVideoPosition(ctx context.Context, id int64) error {
+	ctx, span := f.client.startSpan(ctx, "FilesService.DeleteVideoPosition", attrFileID.Int64(id))
+	defer span.End()
+
 	req, err := f.client.NewRequest(ctx, http.MethodPost, "/v2/files/"+itoa(id)+"/start-from/delete", nil)
 	if err != nil {
 		return err
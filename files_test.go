@@ -707,3 +707,75 @@ Let's go down.
 		t.Errorf("got: %v, want: %v", buf.String(), fileContent)
 	}
 }
+
+func TestFiles_ListWithOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fixture := `
+{
+"files": [
+	{
+		"content_type": "video/x-matroska",
+		"id": 7645645,
+		"name": "MyVideo.mkv",
+		"parent_id": 123,
+		"size": 1155197659,
+		"file_type": "VIDEO",
+		"stream_url": "https://put.io/stream/7645645",
+		"mp4_stream_url": "https://put.io/stream/7645645.mp4",
+		"mp4_status": {"status": "COMPLETED", "percent_done": 100, "size": 1000},
+		"video_metadata": {"width": 1920, "height": 1080, "codec": "h264", "duration": 5400.5, "aspect_ratio": 1.78}
+	}
+],
+"parent": {"id": 123, "name": "MyFolder", "content_type": "application/x-directory"},
+"total": 1,
+"cursor": null,
+"status": "OK"
+}
+`
+	mux.HandleFunc("/v2/files/list", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		q := r.URL.Query()
+		for key, want := range map[string]string{
+			"parent_id":      "123",
+			"sort_by":        SortByNameAsc,
+			"file_type":      "VIDEO,AUDIO",
+			"mp4_status":     "true",
+			"video_metadata": "true",
+			"stream_url":     "true",
+			"total":          "true",
+		} {
+			if got := q.Get(key); got != want {
+				t.Errorf("%v: got: %q, want: %q", key, got, want)
+			}
+		}
+		fmt.Fprintln(w, fixture)
+	})
+
+	files, parent, err := client.Files.ListWithOptions(context.Background(), 123, &ListOptions{
+		SortBy:        SortByNameAsc,
+		FileTypes:     []string{FileTypeVideo, FileTypeAudio},
+		StreamURL:     true,
+		MP4Status:     true,
+		VideoMetadata: true,
+		Total:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || parent.ID != 123 {
+		t.Fatalf("got: %v files, parent %v", len(files), parent.ID)
+	}
+
+	file := files[0]
+	if file.StreamURL != "https://put.io/stream/7645645" {
+		t.Errorf("got: %v", file.StreamURL)
+	}
+	if file.MP4Status == nil || file.MP4Status.Status != MP4StatusCompleted || file.MP4Status.Size != 1000 {
+		t.Errorf("got: %+v", file.MP4Status)
+	}
+	if file.VideoMetadata == nil || file.VideoMetadata.Height != 1080 || file.VideoMetadata.Codec != "h264" {
+		t.Errorf("got: %+v", file.VideoMetadata)
+	}
+}
@@ -12,6 +12,9 @@ type FriendsService struct {
 
 // List lists users friends.
 func (f *FriendsService) List(ctx context.Context) ([]Friend, error) {
+	ctx, span := f.client.startSpan(ctx, "FriendsService.List")
+	defer span.End()
+
 	req, err := f.client.NewRequest(ctx, http.MethodGet, "/v2/friends/list", nil)
 	if err != nil {
 		return nil, err
@@ -31,6 +34,9 @@ func (f *FriendsService) List(ctx context.Context) ([]Friend, error) {
 
 // WaitingRequests lists user's pending friend requests.
 func (f *FriendsService) WaitingRequests(ctx context.Context) ([]Friend, error) {
+	ctx, span := f.client.startSpan(ctx, "FriendsService.WaitingRequests")
+	defer span.End()
+
 	req, err := f.client.NewRequest(ctx, http.MethodGet, "/v2/friends/waiting-requests", nil)
 	if err != nil {
 		return nil, err
@@ -49,6 +55,9 @@ func (f *FriendsService) WaitingRequests(ctx context.Context) ([]Friend, error)
 
 // Request sends a friend request to the given username.
 func (f *FriendsService) Request(ctx context.Context, username string) error {
+	ctx, span := f.client.startSpan(ctx, "FriendsService.Request")
+	defer span.End()
+
 	if username == "" {
 		return ErrEmptyUserName
 	}
@@ -68,6 +77,9 @@ func (f *FriendsService) Request(ctx context.Context, username string) error {
 
 // Approve approves a friend request from the given username.
 func (f *FriendsService) Approve(ctx context.Context, username string) error {
+	ctx, span := f.client.startSpan(ctx, "FriendsService.Approve")
+	defer span.End()
+
 	if username == "" {
 		return ErrEmptyUserName
 	}
@@ -87,6 +99,9 @@ func (f *FriendsService) Approve(ctx context.Context, username string) error {
 
 // Deny denies a friend request from the given username.
 func (f *FriendsService) Deny(ctx context.Context, username string) error {
+	ctx, span := f.client.startSpan(ctx, "FriendsService.Deny")
+	defer span.End()
+
 	if username == "" {
 		return ErrEmptyUserName
 	}
@@ -106,6 +121,9 @@ func (f *FriendsService) Deny(ctx context.Context, username string) error {
 
 // Unfriend removed friend from user's friend list.
 func (f *FriendsService) Unfriend(ctx context.Context, username string) error {
+	ctx, span := f.client.startSpan(ctx, "FriendsService.Unfriend")
+	defer span.End()
+
 	if username == "" {
 		return ErrEmptyUserName
 	}
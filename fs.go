@@ -0,0 +1,204 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FS is a read only file system backed by a put.io account. It implements
+// fs.FS, fs.ReadDirFS, fs.StatFS and fs.ReadFileFS, so it can be used with
+// fs.WalkDir, http.FS, template.ParseFS and the like.
+//
+// Names are resolved relative to the root folder of the FS. Every call makes
+// API requests; set FilesService.PathCache to avoid resolving the same paths
+// over and over.
+type FS struct {
+	files *FilesService
+	ctx   context.Context
+	root  int64
+}
+
+var (
+	_ fs.ReadDirFS   = (*FS)(nil)
+	_ fs.StatFS      = (*FS)(nil)
+	_ fs.ReadFileFS  = (*FS)(nil)
+	_ fs.ReadDirFile = (*fsDir)(nil)
+)
+
+// NewFS returns a file system rooted at the folder with the given ID. Use 0
+// for the user's root folder. All requests are made with ctx.
+func NewFS(ctx context.Context, client *Client, root int64) *FS {
+	return &FS{files: client.Files, ctx: ctx, root: root}
+}
+
+// Open opens the named file. Folders implement fs.ReadDirFile; files
+// implement io.Seeker and io.ReaderAt in addition to fs.File.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	file, err := fsys.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if file.IsDir() {
+		return &fsDir{fsys: fsys, name: name, file: file}, nil
+	}
+	r, err := fsys.files.openFile(fsys.ctx, file, nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &fsFile{FileReader: r, name: name}, nil
+}
+
+// Stat returns a fs.FileInfo describing the named file.
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	file, err := fsys.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return newFileInfo(name, file), nil
+}
+
+// ReadDir reads the named folder and returns its entries sorted by name.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	dir, err := fsys.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !dir.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: ErrNotDir}
+	}
+
+	var entries []fs.DirEntry
+	it := fsys.files.Iterate(fsys.ctx, dir.ID, nil)
+	for it.Next() {
+		entries = append(entries, fs.FileInfoToDirEntry(newFileInfo(it.File().Name, it.File())))
+	}
+	if err := it.Err(); err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// ReadFile reads the named file and returns its contents.
+func (fsys *FS) ReadFile(name string) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return b, nil
+}
+
+// lookup returns the named file.
+func (fsys *FS) lookup(op, name string) (File, error) {
+	if !fs.ValidPath(name) {
+		return File{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	var file File
+	var err error
+	if fsys.root == rootID {
+		file, err = fsys.files.LookupPath(fsys.ctx, name)
+	} else {
+		file, err = fsys.files.Get(fsys.ctx, fsys.root)
+		if err == nil && name != "." {
+			file, err = fsys.files.resolve(fsys.ctx, file, strings.Split(name, "/"), name)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			err = fmt.Errorf("%w: %w", fs.ErrNotExist, err)
+		}
+		return File{}, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	return file, nil
+}
+
+// fileInfo implements fs.FileInfo for a File.
+type fileInfo struct {
+	name string
+	file File
+}
+
+func newFileInfo(name string, file File) *fileInfo {
+	return &fileInfo{name: path.Base(name), file: file}
+}
+
+func (fi *fileInfo) Name() string { return fi.name }
+func (fi *fileInfo) Size() int64  { return fi.file.Size }
+func (fi *fileInfo) IsDir() bool  { return fi.file.IsDir() }
+
+// Sys returns the File.
+func (fi *fileInfo) Sys() interface{} { return fi.file }
+
+func (fi *fileInfo) Mode() fs.FileMode {
+	if fi.file.IsDir() {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (fi *fileInfo) ModTime() time.Time {
+	switch {
+	case fi.file.UpdatedAt != nil:
+		return fi.file.UpdatedAt.Time
+	case fi.file.CreatedAt != nil:
+		return fi.file.CreatedAt.Time
+	}
+	return time.Time{}
+}
+
+// fsDir is an open folder.
+type fsDir struct {
+	fsys *FS
+	name string
+	file File
+	it   *FileIterator
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) { return newFileInfo(d.name, d.file), nil }
+func (d *fsDir) Close() error               { return nil }
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir reads the folder in listing order, as described by
+// fs.ReadDirFile.
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.it == nil {
+		d.it = d.fsys.files.Iterate(d.fsys.ctx, d.file.ID, nil)
+	}
+	entries := []fs.DirEntry{}
+	for (n <= 0 || len(entries) < n) && d.it.Next() {
+		file := d.it.File()
+		entries = append(entries, fs.FileInfoToDirEntry(newFileInfo(file.Name, file)))
+	}
+	if err := d.it.Err(); err != nil {
+		return entries, &fs.PathError{Op: "readdir", Path: d.name, Err: err}
+	}
+	if n > 0 && len(entries) == 0 {
+		return entries, io.EOF
+	}
+	return entries, nil
+}
+
+// fsFile is an open file.
+type fsFile struct {
+	*FileReader
+	name string
+}
+
+func (f *fsFile) Stat() (fs.FileInfo, error) { return newFileInfo(f.name, f.file), nil }
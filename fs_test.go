@@ -0,0 +1,76 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFS(t *testing.T) {
+	setup()
+	defer teardown()
+	newFakeTree("/a.txt", "/docs/readme.md", "/docs/notes/todo.txt", "/empty/").register(mux)
+
+	err := fstest.TestFS(NewFS(context.Background(), client, 0),
+		"a.txt", "docs/readme.md", "docs/notes/todo.txt", "empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFS_subfolder(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree("/docs/readme.md", "/docs/notes/todo.txt")
+	tree.register(mux)
+	docs, _ := tree.child(0, "docs")
+	fsys := NewFS(context.Background(), client, docs.ID)
+
+	b, err := fs.ReadFile(fsys, "notes/todo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "todo.txt" {
+		t.Errorf("got: %q, want: todo.txt", b)
+	}
+
+	_, err = fsys.Stat("notes/missing")
+	if !errors.Is(err, fs.ErrNotExist) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("got: %v, want: %v", err, fs.ErrNotExist)
+	}
+	_, err = fsys.Open("../docs")
+	if !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("got: %v, want: %v", err, fs.ErrInvalid)
+	}
+}
+
+func TestFS_seek(t *testing.T) {
+	setup()
+	defer teardown()
+	newFakeTree("/0123456789").register(mux)
+
+	f, err := NewFS(context.Background(), client, 0).Open("0123456789")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	rs := f.(io.ReadSeeker)
+	_, err = rs.Seek(-4, io.SeekEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(rs)
+	if err != nil || string(b) != "6789" {
+		t.Errorf("got: %q %v, want: 6789", b, err)
+	}
+
+	p := make([]byte, 3)
+	n, err := f.(io.ReaderAt).ReadAt(p, 2)
+	if err != nil || string(p[:n]) != "234" {
+		t.Errorf("got: %q %v, want: 234", p[:n], err)
+	}
+}
@@ -0,0 +1,274 @@
+package putio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Sort orders accepted by ListOptions.SortBy.
+const (
+	SortByNameAsc      = "NAME_ASC"
+	SortByNameDesc     = "NAME_DESC"
+	SortBySizeAsc      = "SIZE_ASC"
+	SortBySizeDesc     = "SIZE_DESC"
+	SortByDateAsc      = "DATE_ASC"
+	SortByDateDesc     = "DATE_DESC"
+	SortByModifiedAsc  = "MODIFIED_ASC"
+	SortByModifiedDesc = "MODIFIED_DESC"
+	SortByTypeAsc      = "TYPE_ASC"
+	SortByTypeDesc     = "TYPE_DESC"
+)
+
+// defaultPerPage is the page size used when ListOptions.PerPage is zero.
+const defaultPerPage = 1000
+
+// ListOptions are the server side parameters of a folder listing. The zero
+// value lists all files in the server's default order, 1000 at a time.
+type ListOptions struct {
+	// PerPage is the number of files fetched with each request.
+	PerPage int
+
+	// SortBy is one of the SortBy constants.
+	SortBy string
+
+	// FileTypes limits the listing to the given file types, such as
+	// FileTypeVideo and FileTypeAudio.
+	FileTypes []string
+
+	// ContentType limits the listing to files with the given MIME type.
+	ContentType string
+
+	// Hidden includes hidden files.
+	Hidden bool
+
+	// StreamURL fills File.StreamURL.
+	StreamURL bool
+
+	// MP4StreamURL fills File.MP4StreamURL for videos that have an MP4
+	// version.
+	MP4StreamURL bool
+
+	// MP4Status fills File.MP4Status.
+	MP4Status bool
+
+	// VideoMetadata fills File.VideoMetadata.
+	VideoMetadata bool
+
+	// Total asks the server for the number of files in the folder, which is
+	// available from FileIterator.Total.
+	Total bool
+}
+
+// query returns the query parameters of the first page.
+func (o *ListOptions) query(parentID int64) url.Values {
+	q := url.Values{}
+	q.Set("parent_id", itoa(parentID))
+	q.Set("per_page", strconv.Itoa(o.perPage()))
+	if o.SortBy != "" {
+		q.Set("sort_by", o.SortBy)
+	}
+	if len(o.FileTypes) > 0 {
+		q.Set("file_type", strings.Join(o.FileTypes, ","))
+	}
+	if o.ContentType != "" {
+		q.Set("content_type", o.ContentType)
+	}
+	if o.Hidden {
+		q.Set("hidden", "true")
+	}
+	if o.StreamURL {
+		q.Set("stream_url", "true")
+	}
+	if o.MP4StreamURL {
+		q.Set("mp4_stream_url", "true")
+	}
+	if o.MP4Status {
+		q.Set("mp4_status", "true")
+	}
+	if o.VideoMetadata {
+		q.Set("video_metadata", "true")
+	}
+	if o.Total {
+		q.Set("total", "true")
+	}
+	return q
+}
+
+func (o *ListOptions) perPage() int {
+	if o.PerPage <= 0 {
+		return defaultPerPage
+	}
+	return o.PerPage
+}
+
+// FileIterator lists the children of a folder one page at a time, so that
+// large folders are never held in memory at once. Use it like this:
+//
+//	it := client.Files.Iterate(ctx, folderID, nil)
+//	for it.Next() {
+//		file := it.File()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+//
+// Stopping before Next returns false is allowed and does not leak resources.
+// A FileIterator must not be used concurrently.
+type FileIterator struct {
+	files    *FilesService
+	ctx      context.Context
+	opts     ListOptions
+	parentID int64
+
+	page   []File
+	index  int
+	parent File
+	total  int64
+	cursor string
+	// fetched is false until the first page is fetched
+	fetched bool
+	err     error
+}
+
+// Iterate returns an iterator over the children of the folder with the given
+// ID. Nil opts is equivalent to the zero ListOptions. No request is made
+// until Next is called. The iterator stops early when ctx is done.
+func (f *FilesService) Iterate(ctx context.Context, id int64, opts *ListOptions) *FileIterator {
+	it := &FileIterator{files: f, ctx: ctx, parentID: id}
+	if opts != nil {
+		it.opts = *opts
+	}
+	return it
+}
+
+// ResumeIterate returns an iterator that continues a listing from a cursor
+// previously returned by FileIterator.Cursor. Parent is not available on a
+// resumed iterator. Options other than PerPage are fixed by the original
+// listing.
+func (f *FilesService) ResumeIterate(ctx context.Context, cursor string, opts *ListOptions) *FileIterator {
+	it := f.Iterate(ctx, 0, opts)
+	it.cursor = cursor
+	it.fetched = true
+	return it
+}
+
+// Next advances the iterator to the next file, fetching the next page when
+// the current one is exhausted. It returns false at the end of the listing or
+// on error.
+func (it *FileIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.index >= len(it.page) {
+		if it.fetched && it.cursor == "" {
+			return false
+		}
+		it.err = it.fetch()
+		if it.err != nil {
+			return false
+		}
+	}
+	it.index++
+	return true
+}
+
+// File returns the current file.
+func (it *FileIterator) File() File {
+	if it.index == 0 || it.index > len(it.page) {
+		return File{}
+	}
+	return it.page[it.index-1]
+}
+
+// Parent returns the listed folder. It is available once Next has been
+// called.
+func (it *FileIterator) Parent() File {
+	return it.parent
+}
+
+// Total returns the number of files in the folder. It is only available when
+// ListOptions.Total is set and Next has been called.
+func (it *FileIterator) Total() int64 {
+	return it.total
+}
+
+// Cursor returns the cursor of the page after the current one, or an empty
+// string if the current page is the last one. Resuming from it with
+// ResumeIterate skips the files left on the current page, so save it when
+// PageDone reports true.
+func (it *FileIterator) Cursor() string {
+	return it.cursor
+}
+
+// PageDone reports whether the current file is the last one of its page.
+func (it *FileIterator) PageDone() bool {
+	return it.index >= len(it.page)
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *FileIterator) Err() error {
+	return it.err
+}
+
+// fetch fetches the next page.
+func (it *FileIterator) fetch() error {
+	err := it.ctx.Err()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	ctx, span := it.files.client.startSpan(it.ctx, "FilesService.ListPage", attrFileID.Int64(it.parentID))
+	defer span.End()
+
+	var req *http.Request
+	if !it.fetched {
+		req, err = it.files.client.NewRequest(ctx, http.MethodGet, "/v2/files/list?"+it.opts.query(it.parentID).Encode(), nil)
+	} else {
+		var body []byte
+		body, err = json.Marshal(struct {
+			Cursor  string `json:"cursor"`
+			PerPage int    `json:"per_page"`
+		}{it.cursor, it.opts.perPage()})
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		// a POST that only reads, and so may be retried
+		ctx = withReadOnly(ctx)
+		req, err = it.files.client.NewRequest(ctx, http.MethodPost, "/v2/files/list/continue", bytes.NewReader(body))
+		if req != nil {
+			req.Header.Set("content-type", "application/json")
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	var r struct {
+		Files  []File `json:"files"`
+		Parent *File  `json:"parent"`
+		Total  *int64 `json:"total"`
+		Cursor string `json:"cursor"`
+	}
+	_, err = it.files.client.Do(req, &r) // nolint:bodyclose
+	if err != nil {
+		return err
+	}
+	if r.Parent != nil {
+		it.parent = *r.Parent
+	}
+	if r.Total != nil {
+		it.total = *r.Total
+	}
+	it.page = r.Files
+	it.index = 0
+	it.cursor = r.Cursor
+	it.fetched = true
+	return nil
+}
@@ -0,0 +1,195 @@
+package putio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// setupPagedList serves a folder of 5 files, 2 per page, and returns a pointer
+// to the number of requests made.
+func setupPagedList(t *testing.T) *int {
+	var requests int
+	page := func(w http.ResponseWriter, ids []int, cursor string) {
+		files := make([]File, 0, len(ids))
+		for _, id := range ids {
+			files = append(files, File{ID: int64(id), Name: fmt.Sprintf("file%d", id)})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"files":  files,
+			"parent": File{ID: 10, Name: "folder"},
+			"cursor": cursor,
+		})
+	}
+
+	mux.HandleFunc("/v2/files/list", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		testMethod(t, r, http.MethodGet)
+		q := r.URL.Query()
+		if q.Get("parent_id") != "10" || q.Get("per_page") != "2" {
+			t.Errorf("got query: %v", r.URL.RawQuery)
+		}
+		page(w, []int{1, 2}, "c1")
+	})
+	mux.HandleFunc("/v2/files/list/continue", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		testMethod(t, r, http.MethodPost)
+		var body struct {
+			Cursor  string `json:"cursor"`
+			PerPage int    `json:"per_page"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.PerPage != 2 {
+			t.Errorf("got per_page: %v, want: 2", body.PerPage)
+		}
+		switch body.Cursor {
+		case "c1":
+			page(w, []int{3, 4}, "c2")
+		case "c2":
+			page(w, []int{5}, "")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	return &requests
+}
+
+func TestFiles_Iterate(t *testing.T) {
+	setup()
+	defer teardown()
+	requests := setupPagedList(t)
+
+	it := client.Files.Iterate(context.Background(), 10, &ListOptions{PerPage: 2})
+	var ids []int64
+	for it.Next() {
+		ids = append(ids, it.File().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[1 2 3 4 5]" {
+		t.Errorf("got: %v, want: [1 2 3 4 5]", ids)
+	}
+	if it.Parent().ID != 10 {
+		t.Errorf("got parent: %v, want: 10", it.Parent().ID)
+	}
+	if *requests != 3 {
+		t.Errorf("got: %v requests, want: 3", *requests)
+	}
+}
+
+func TestFiles_Iterate_earlyStopAndResume(t *testing.T) {
+	setup()
+	defer teardown()
+	requests := setupPagedList(t)
+
+	it := client.Files.Iterate(context.Background(), 10, &ListOptions{PerPage: 2})
+	var cursor string
+	for it.Next() {
+		if it.PageDone() {
+			cursor = it.Cursor()
+			break
+		}
+	}
+	if cursor != "c1" || *requests != 1 {
+		t.Fatalf("got cursor: %q after %d requests, want: c1 after 1", cursor, *requests)
+	}
+
+	it = client.Files.ResumeIterate(context.Background(), cursor, &ListOptions{PerPage: 2})
+	var ids []int64
+	for it.Next() {
+		ids = append(ids, it.File().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[3 4 5]" {
+		t.Errorf("got: %v, want: [3 4 5]", ids)
+	}
+}
+
+func TestFiles_Iterate_canceled(t *testing.T) {
+	setup()
+	defer teardown()
+	requests := setupPagedList(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := client.Files.Iterate(ctx, 10, &ListOptions{PerPage: 2})
+	var n int
+	for it.Next() {
+		n++
+		cancel()
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("got: %v, want: %v", it.Err(), context.Canceled)
+	}
+	if n != 2 || *requests != 1 {
+		t.Errorf("got: %v files and %v requests, want: 2 and 1", n, *requests)
+	}
+}
+
+func TestFiles_Iterate_retryContinue(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+
+	mux.HandleFunc("/v2/files/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"files":[{"id":1}],"cursor":"c1"}`)
+	})
+	var calls int
+	mux.HandleFunc("/v2/files/list/continue", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprintln(w, `{"files":[{"id":2}],"cursor":""}`)
+	})
+
+	files, _, err := client.Files.List(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || calls != 2 {
+		t.Errorf("got: %v files after %v calls, want: 2 after 2", len(files), calls)
+	}
+}
+
+func TestListOptions_query(t *testing.T) {
+	opts := ListOptions{
+		SortBy:       SortBySizeDesc,
+		FileTypes:    []string{FileTypeVideo, FileTypeAudio},
+		ContentType:  "video/mp4",
+		Hidden:       true,
+		StreamURL:    true,
+		MP4StreamURL: true,
+	}
+	want := "content_type=video%2Fmp4&file_type=VIDEO%2CAUDIO&hidden=true&mp4_stream_url=true" +
+		"&parent_id=3&per_page=1000&sort_by=SIZE_DESC&stream_url=true"
+	if got := opts.query(3).Encode(); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestFiles_Iterate_total(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/files/list", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("total") != "true" {
+			t.Errorf("total is not requested: %v", r.URL.RawQuery)
+		}
+		fmt.Fprintln(w, `{"files":[{"id":1}],"parent":{"id":0},"total":1,"cursor":null}`)
+	})
+
+	it := client.Files.Iterate(context.Background(), 0, &ListOptions{Total: true})
+	for it.Next() {
+	}
+	if it.Err() != nil || it.Total() != 1 {
+		t.Errorf("got: %v %v, want: total 1", it.Err(), it.Total())
+	}
+}
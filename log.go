@@ -0,0 +1,124 @@
+package putio
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redacted replaces secrets in log records.
+const redacted = "REDACTED"
+
+// sensitiveHeaders are never logged in clear text.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// discardLogger is used when Client.Logger is nil.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+
+// attemptKey is the context key of the attempt number set by Client.Do.
+type attemptKey struct{}
+
+func (c *Client) logger() *slog.Logger {
+	if c.Logger == nil {
+		return discardLogger
+	}
+	return c.Logger
+}
+
+// logging is the middleware that writes a record for each HTTP attempt.
+// Successful requests are logged at Info level, failed ones at Warn level.
+// Request headers are included at Debug level.
+func (c *Client) logging(next Doer) Doer {
+	return DoerFunc(func(r *http.Request) (*http.Response, error) {
+		logger := c.logger()
+		ctx := r.Context()
+		if !logger.Enabled(ctx, slog.LevelWarn) {
+			return next.Do(r)
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("url", redactURL(r.URL)),
+		}
+		if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+			attrs = append(attrs, slog.Int("attempt", attempt))
+		}
+		if offset := r.Header.Get("Upload-Offset"); offset != "" {
+			attrs = append(attrs, slog.String("upload_offset", offset))
+		}
+		if logger.Enabled(ctx, slog.LevelDebug) {
+			attrs = append(attrs, slog.Any("headers", redactHeader(r.Header)))
+		}
+
+		start := time.Now()
+		resp, err := next.Do(r)
+		attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+		level := slog.LevelInfo
+		if err != nil {
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("error", err.Error()))
+		} else {
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			if resp.StatusCode >= 400 {
+				level = slog.LevelWarn
+			}
+		}
+		logger.LogAttrs(ctx, level, "putio request", attrs...)
+		return resp, err
+	})
+}
+
+// redactURL returns u as a string with credentials and token-like query
+// parameters replaced.
+func redactURL(u *url.URL) string {
+	v := *u
+	if v.User != nil {
+		v.User = url.User(redacted)
+	}
+	if v.RawQuery != "" {
+		q := v.Query()
+		for key := range q {
+			if isSensitiveParam(key) {
+				q.Set(key, redacted)
+			}
+		}
+		v.RawQuery = q.Encode()
+	}
+	return v.String()
+}
+
+func isSensitiveParam(key string) bool {
+	key = strings.ToLower(key)
+	switch key {
+	case "code", "key", "api_key", "apikey", "sig", "signature":
+		return true
+	}
+	for _, s := range []string{"token", "secret", "password"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeader returns a copy of h with sensitive values replaced.
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, key := range sensitiveHeaders {
+		if h.Get(key) != "" {
+			h.Set(key, redacted)
+		}
+	}
+	return h
+}
+
+// logAttempt returns ctx annotated with the attempt number, which is logged
+// with each request.
+func logAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
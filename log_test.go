@@ -0,0 +1,106 @@
+package putio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func setupLogger() *bytes.Buffer {
+	var buf bytes.Buffer
+	client.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return &buf
+}
+
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestClient_Logger(t *testing.T) {
+	setup()
+	defer teardown()
+	buf := setupLogger()
+	client.ExtraHeaders.Set("Authorization", "Bearer secret")
+
+	mux.HandleFunc("/v2/account/info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"info":{}, "status":"OK"}`)
+	})
+
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/v2/account/info?oauth_token=secret&x=1", nil)
+	_, err := client.Do(req, &struct{}{}) // nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("secret is logged: %s", buf.String())
+	}
+
+	rec := logRecords(t, buf)[0]
+	if rec["method"] != http.MethodGet {
+		t.Errorf("got method: %v, want: %v", rec["method"], http.MethodGet)
+	}
+	if rec["status"] != float64(http.StatusOK) {
+		t.Errorf("got status: %v, want: %v", rec["status"], http.StatusOK)
+	}
+	if rec["attempt"] != float64(1) {
+		t.Errorf("got attempt: %v, want: 1", rec["attempt"])
+	}
+	if url, _ := rec["url"].(string); !strings.Contains(url, "oauth_token=REDACTED") || !strings.Contains(url, "x=1") {
+		t.Errorf("got url: %v", rec["url"])
+	}
+	headers, _ := rec["headers"].(map[string]interface{})
+	if auth, _ := headers["Authorization"].([]interface{}); len(auth) != 1 || auth[0] != redacted {
+		t.Errorf("got authorization: %v, want: %v", headers["Authorization"], redacted)
+	}
+}
+
+func TestUpload_Logger(t *testing.T) {
+	setup()
+	defer teardown()
+	buf := setupLogger()
+
+	var legacy []string
+	client.Upload.Log = func(message string) {
+		legacy = append(legacy, message)
+	}
+
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("putio-file-id", "7")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, _, err := client.Upload.SendFile(context.Background(), strings.NewReader("hello"), server.URL+"/files/abc", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, rec := range logRecords(t, buf) {
+		if rec["msg"] == "putio request" && rec["method"] == http.MethodPatch {
+			found = true
+			if rec["upload_offset"] != "3" {
+				t.Errorf("got upload_offset: %v, want: 3", rec["upload_offset"])
+			}
+		}
+	}
+	if !found {
+		t.Error("request is not logged")
+	}
+	if len(legacy) == 0 || !strings.HasPrefix(legacy[0], "sending file location=") {
+		t.Errorf("got legacy messages: %q", legacy)
+	}
+}
@@ -0,0 +1,52 @@
+package putio
+
+import (
+	"net/http"
+)
+
+// Doer sends an HTTP request and returns the HTTP response. *http.Client
+// satisfies this interface.
+type Doer interface {
+	Do(r *http.Request) (*http.Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as Doer.
+type DoerFunc func(r *http.Request) (*http.Response, error)
+
+// Do calls f(r).
+func (f DoerFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Middleware wraps a Doer to inspect or modify requests and responses. It is
+// used for cross-cutting concerns such as logging, authentication, metrics,
+// fault injection and request signing.
+//
+// Middlewares see every request sent by the Client, including the requests
+// made by UploadService. They are called once per attempt, so a request that
+// is retried passes through them again. Error responses from the API are
+// returned as responses; they are converted to errors after the chain.
+type Middleware func(next Doer) Doer
+
+// Use appends mw to the client's middleware chain. It must not be called
+// concurrently with requests.
+func (c *Client) Use(mw ...Middleware) {
+	c.Middleware = append(c.Middleware, mw...)
+}
+
+// doer returns the client's middleware chain wrapped around the rate limited
+// HTTP client. The first middleware is the outermost one. Logging and
+// telemetry, if enabled, wrap the whole chain.
+func (c *Client) doer() Doer {
+	var d Doer = DoerFunc(c.limitedDo)
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		d = c.Middleware[i](d)
+	}
+	if c.Logger != nil {
+		d = c.logging(d)
+	}
+	if c.Telemetry != nil {
+		d = c.Telemetry.middleware(d)
+	}
+	return d
+}
@@ -0,0 +1,108 @@
+package putio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(r *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				r.Header.Add("X-Chain", name)
+				return next.Do(r)
+			})
+		}
+	}
+	client.Use(trace("a"), trace("b"))
+
+	mux.HandleFunc("/v2/account/info", func(w http.ResponseWriter, r *http.Request) {
+		if got := strings.Join(r.Header.Values("X-Chain"), ","); got != "a,b" {
+			t.Errorf("got: %q, want: %q", got, "a,b")
+		}
+		fmt.Fprintln(w, `{"info":{}, "status":"OK"}`)
+	})
+
+	_, err := client.Account.Info(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(order, ","); got != "a,b" {
+		t.Errorf("got: %q, want: %q", got, "a,b")
+	}
+}
+
+func TestClient_Middleware_upload(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var seen []string
+	client.Use(func(next Doer) Doer {
+		return DoerFunc(func(r *http.Request) (*http.Response, error) {
+			seen = append(seen, r.Method+" "+r.URL.Path)
+			return next.Do(r)
+		})
+	})
+
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodHead)
+		w.Header().Set("Upload-Offset", "42")
+	})
+
+	offset, err := client.Upload.GetOffset(context.Background(), server.URL+"/files/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 42 {
+		t.Errorf("got: %v, want: 42", offset)
+	}
+	if len(seen) != 1 || seen[0] != "HEAD /files/abc" {
+		t.Errorf("got: %v, want: [HEAD /files/abc]", seen)
+	}
+}
+
+func TestClient_Middleware_faultInjection(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+
+	var injected bool
+	client.Use(func(next Doer) Doer {
+		return DoerFunc(func(r *http.Request) (*http.Response, error) {
+			if !injected {
+				injected = true
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Header:     http.Header{"Retry-After": []string{"0"}},
+					Body:       io.NopCloser(strings.NewReader("")),
+					Request:    r,
+				}, nil
+			}
+			return next.Do(r)
+		})
+	})
+
+	mux.HandleFunc("/v2/account/info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"info":{"username":"naber"}, "status":"OK"}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	info, err := client.Account.Info(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Username != "naber" {
+		t.Errorf("got: %q, want: %q", info.Username, "naber")
+	}
+}
@@ -0,0 +1,76 @@
+package putio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// FlexInt is an integer that the API sends as a JSON number, as a string
+// holding a number, or as null. Null and the empty string decode as 0. It
+// is encoded as a JSON number.
+type FlexInt int64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *FlexInt) UnmarshalJSON(b []byte) error {
+	s, ok, err := numberText(b)
+	if err != nil || !ok {
+		*n = 0
+		return err
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		*n = FlexInt(i)
+		return nil
+	}
+	// numbers like 1.0 or 1e3
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return fmt.Errorf("%w: cannot decode %s as an integer", ErrUnexpected, b)
+	}
+	*n = FlexInt(f)
+	return nil
+}
+
+// FlexFloat is a number that the API sends as a JSON number, as a string
+// holding a number, or as null. Null and the empty string decode as 0. It is
+// encoded as a JSON number.
+type FlexFloat float64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *FlexFloat) UnmarshalJSON(b []byte) error {
+	s, ok, err := numberText(b)
+	if err != nil || !ok {
+		*n = 0
+		return err
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("%w: cannot decode %s as a number", ErrUnexpected, b)
+	}
+	*n = FlexFloat(f)
+	return nil
+}
+
+// numberText returns the text of a JSON number or of a string holding one.
+// It reports false for null and the empty string.
+func numberText(b []byte) (string, bool, error) {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return "", false, nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		err := json.Unmarshal(b, &s)
+		if err != nil {
+			return "", false, fmt.Errorf("%w", err)
+		}
+		b = bytes.TrimSpace([]byte(s))
+		if len(b) == 0 {
+			return "", false, nil
+		}
+	}
+	return string(b), true, nil
+}
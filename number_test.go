@@ -0,0 +1,159 @@
+package putio
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFlexInt_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want FlexInt
+		err  bool
+	}{
+		{`42`, 42, false},
+		{`-7`, -7, false},
+		{`"42"`, 42, false},
+		{`" 42 "`, 42, false},
+		{`1.0`, 1, false},
+		{`"1e3"`, 1000, false},
+		{`null`, 0, false},
+		{`""`, 0, false},
+		{`1.5`, 0, true},
+		{`"abc"`, 0, true},
+		{`"NaN"`, 0, true},
+		{`1e300`, 0, true},
+		{`true`, 0, true},
+	}
+	for _, tt := range tests {
+		n := FlexInt(99)
+		err := json.Unmarshal([]byte(tt.in), &n)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: got error: %v", tt.in, err)
+			continue
+		}
+		if err != nil && !errors.Is(err, ErrUnexpected) {
+			t.Errorf("%s: got: %v, want: %v", tt.in, err, ErrUnexpected)
+		}
+		if !tt.err && n != tt.want {
+			t.Errorf("%s: got: %v, want: %v", tt.in, n, tt.want)
+		}
+	}
+}
+
+func TestFlexFloat_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want FlexFloat
+		err  bool
+	}{
+		{`0.25`, 0.25, false},
+		{`"0.25"`, 0.25, false},
+		{`"1.50"`, 1.5, false},
+		{`3`, 3, false},
+		{`null`, 0, false},
+		{`""`, 0, false},
+		{`"Inf"`, 0, true},
+		{`"ratio"`, 0, true},
+		{`[]`, 0, true},
+	}
+	for _, tt := range tests {
+		var n FlexFloat
+		err := json.Unmarshal([]byte(tt.in), &n)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: got error: %v", tt.in, err)
+			continue
+		}
+		if !tt.err && n != tt.want {
+			t.Errorf("%s: got: %v, want: %v", tt.in, n, tt.want)
+		}
+	}
+}
+
+func TestTransfer_UnmarshalJSON_mixedNumbers(t *testing.T) {
+	var transfers []Transfer
+	err := json.Unmarshal([]byte(`[
+		{"current_ratio": "1.50", "availability": "100", "estimated_time": 90, "size": "1024"},
+		{"current_ratio": 0.5, "availability": null, "estimated_time": null, "subscription_id": null}
+	]`), &transfers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr := transfers[0]; tr.CurrentRatio != 1.5 || tr.Availability != 100 || tr.ETA() != 90*time.Second || tr.Size != 1024 {
+		t.Errorf("got: %+v", tr)
+	}
+	if tr := transfers[1]; tr.CurrentRatio != 0.5 || tr.Availability != 0 || tr.ETA() != 0 {
+		t.Errorf("got: %+v", tr)
+	}
+
+	b, err := json.Marshal(Transfer{CurrentRatio: 1.5, Size: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	_ = json.Unmarshal(b, &m)
+	if m["current_ratio"] != 1.5 || m["size"] != float64(10) {
+		t.Errorf("got: %s", b)
+	}
+}
+
+func FuzzFlexInt(f *testing.F) {
+	for _, s := range []string{`42`, `"42"`, `null`, `""`, `1.0`, `"1e3"`, `1.5`, `"x"`, `-9223372036854775808`} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var n FlexInt
+		if json.Unmarshal(b, &n) != nil {
+			return
+		}
+		out, err := json.Marshal(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var again FlexInt
+		err = json.Unmarshal(out, &again)
+		if err != nil || again != n {
+			t.Errorf("%s: decoded %v, then %v from %s: %v", b, n, again, out, err)
+		}
+	})
+}
+
+func FuzzFlexFloat(f *testing.F) {
+	for _, s := range []string{`0.25`, `"0.25"`, `null`, `""`, `1e308`, `"Inf"`, `"0x1p-2"`} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var n FlexFloat
+		if json.Unmarshal(b, &n) != nil {
+			return
+		}
+		if math.IsNaN(float64(n)) || math.IsInf(float64(n), 0) {
+			t.Fatalf("%s: decoded %v", b, n)
+		}
+		out, err := json.Marshal(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var again FlexFloat
+		err = json.Unmarshal(out, &again)
+		if err != nil || again != n {
+			t.Errorf("%s: decoded %v, then %v from %s: %v", b, n, again, out, err)
+		}
+	})
+}
+
+func FuzzTransfer_UnmarshalJSON(f *testing.F) {
+	f.Add([]byte(`{"id": 1, "current_ratio": "0.00", "availability": null, "status": "SEEDING"}`))
+	f.Add([]byte(`{"estimated_time": "12", "percent_done": 99.0, "created_at": "2016-07-15T09:45:15"}`))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var tr Transfer
+		_ = json.Unmarshal(b, &tr)
+		var info AccountInfo
+		_ = json.Unmarshal(b, &info)
+		var file File
+		_ = json.Unmarshal(b, &file)
+	})
+}
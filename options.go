@@ -0,0 +1,227 @@
+package putio
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+)
+
+// Option configures a Client created by New.
+type Option func(*options) error
+
+// options collects the configuration given to New. Settings that end up in
+// Client fields are written directly to client; the HTTP client is assembled
+// once all options are applied.
+type options struct {
+	client      *Client
+	httpClient  *http.Client
+	transport   http.RoundTripper
+	tokenSource oauth2.TokenSource
+}
+
+// New returns a new Put.io API client configured by opts. Unlike setting the
+// exported fields of a Client, options are applied before the client is
+// returned, so they never race with requests in flight.
+//
+// Without any option the client is equivalent to NewClient(nil).
+func New(opts ...Option) (*Client, error) {
+	o := &options{client: NewClient(nil)}
+	for _, opt := range opts {
+		err := opt(o)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := o.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if o.transport != nil || o.tokenSource != nil {
+		// never modify the given or the default client
+		hc := *httpClient
+		if o.transport != nil {
+			hc.Transport = o.transport
+		}
+		if o.tokenSource != nil {
+			hc.Transport = &oauth2.Transport{Source: o.tokenSource, Base: hc.Transport}
+		}
+		httpClient = &hc
+	}
+	o.client.client = httpClient
+
+	return o.client, nil
+}
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) error {
+		o.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTransport sets the transport of the HTTP client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) error {
+		o.transport = transport
+		return nil
+	}
+}
+
+// WithTokenSource authenticates every request with an OAuth2 token from ts.
+func WithTokenSource(ts oauth2.TokenSource) Option {
+	return func(o *options) error {
+		o.tokenSource = ts
+		return nil
+	}
+}
+
+// WithToken authenticates every request with the given OAuth2 access token.
+func WithToken(token string) Option {
+	return WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+}
+
+// WithBaseURL sets the base URL for API requests.
+func WithBaseURL(rawURL string) Option {
+	return func(o *options) (err error) {
+		o.client.BaseURL, err = parseAbsoluteURL(rawURL)
+		return err
+	}
+}
+
+// WithUploadURL sets the base URL of the upload server.
+func WithUploadURL(rawURL string) Option {
+	return func(o *options) (err error) {
+		o.client.UploadURL, err = parseAbsoluteURL(rawURL)
+		return err
+	}
+}
+
+// WithTusURL sets the endpoint of the tus server.
+func WithTusURL(rawURL string) Option {
+	return func(o *options) (err error) {
+		o.client.TusURL, err = parseAbsoluteURL(rawURL)
+		return err
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) error {
+		o.client.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithHeader adds a header that is sent with every request.
+func WithHeader(key, value string) Option {
+	return func(o *options) error {
+		o.client.ExtraHeaders.Add(key, value)
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request. Zero means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) error {
+		if timeout < 0 {
+			return ErrNegativeTimeValue
+		}
+		o.client.Timeout = timeout
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the retry policy. Nil disables retries.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(o *options) error {
+		o.client.RetryPolicy = policy
+		return nil
+	}
+}
+
+// WithLogger sets the logger that receives a record for each request.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) error {
+		o.client.Logger = logger
+		return nil
+	}
+}
+
+// WithRateLimiter sets the rate limiter for requests to the API server.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(o *options) error {
+		o.client.RateLimiter = limiter
+		return nil
+	}
+}
+
+// WithUploadRateLimiter sets the rate limiter for requests to the upload
+// server.
+func WithUploadRateLimiter(limiter RateLimiter) Option {
+	return func(o *options) error {
+		o.client.UploadRateLimiter = limiter
+		return nil
+	}
+}
+
+// WithUploadBandwidth limits the rate at which request bodies are sent, in
+// bytes per second.
+func WithUploadBandwidth(limiter *TokenBucket) Option {
+	return func(o *options) error {
+		o.client.UploadBandwidth = limiter
+		return nil
+	}
+}
+
+// WithDownloadBandwidth limits the rate at which response bodies are
+// received, in bytes per second.
+func WithDownloadBandwidth(limiter *TokenBucket) Option {
+	return func(o *options) error {
+		o.client.DownloadBandwidth = limiter
+		return nil
+	}
+}
+
+// WithMiddleware appends mw to the middleware chain.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(o *options) error {
+		o.client.Use(mw...)
+		return nil
+	}
+}
+
+// WithTelemetry enables OpenTelemetry instrumentation. Nil providers fall
+// back to the global ones.
+func WithTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) Option {
+	return func(o *options) (err error) {
+		o.client.Telemetry, err = NewTelemetry(tp, mp)
+		return err
+	}
+}
+
+// WithPathCache enables caching of the paths resolved by FilesService.
+func WithPathCache(cache *PathCache) Option {
+	return func(o *options) error {
+		o.client.Files.PathCache = cache
+		return nil
+	}
+}
+
+func parseAbsoluteURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidURL, rawURL)
+	}
+	return u, nil
+}
@@ -0,0 +1,124 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestNew_defaults(t *testing.T) {
+	cl, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cl.BaseURL.String() != defaultBaseURL {
+		t.Errorf("got: %v, want: %v", cl.BaseURL, defaultBaseURL)
+	}
+	if cl.UploadURL.String() != defaultUploadURL {
+		t.Errorf("got: %v, want: %v", cl.UploadURL, defaultUploadURL)
+	}
+	if cl.TusURL.String() != defaultTusURL {
+		t.Errorf("got: %v, want: %v", cl.TusURL, defaultTusURL)
+	}
+	if cl.client != http.DefaultClient {
+		t.Error("default HTTP client is not used")
+	}
+}
+
+func TestNew_options(t *testing.T) {
+	limiter := NewTokenBucket(10, 1)
+	logger := slog.Default()
+	cl, err := New(
+		WithBaseURL("https://api.example.com"),
+		WithUploadURL("https://upload.example.com"),
+		WithTusURL("https://upload.example.com/files/"),
+		WithUserAgent("test-agent"),
+		WithHeader("X-Test", "1"),
+		WithTimeout(time.Second),
+		WithRetryPolicy(nil),
+		WithLogger(logger),
+		WithRateLimiter(limiter),
+		WithUploadRateLimiter(limiter),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cl.BaseURL.Host != "api.example.com" || cl.UploadURL.Host != "upload.example.com" {
+		t.Errorf("got: %v %v", cl.BaseURL, cl.UploadURL)
+	}
+	if cl.TusURL.String() != "https://upload.example.com/files/" {
+		t.Errorf("got: %v", cl.TusURL)
+	}
+	if cl.UserAgent != "test-agent" || cl.ExtraHeaders.Get("X-Test") != "1" {
+		t.Errorf("got: %v %v", cl.UserAgent, cl.ExtraHeaders)
+	}
+	if cl.Timeout != time.Second || cl.RetryPolicy != nil || cl.Logger != logger {
+		t.Errorf("got: %v %v %v", cl.Timeout, cl.RetryPolicy, cl.Logger)
+	}
+	if cl.RateLimiter != limiter || cl.UploadRateLimiter != limiter {
+		t.Error("rate limiters are not set")
+	}
+}
+
+func TestNew_invalidOption(t *testing.T) {
+	for _, opt := range []Option{
+		WithBaseURL("/relative"),
+		WithUploadURL(":"),
+		WithTimeout(-time.Second),
+	} {
+		_, err := New(opt)
+		if err == nil {
+			t.Error("must not return nil")
+		}
+	}
+
+	_, err := New(WithBaseURL("api.put.io"))
+	if !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("got: %v, want: %v", err, ErrInvalidURL)
+	}
+}
+
+func TestNew_tokenAndTransport(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/account/info", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, "Authorization", "Bearer t0ken")
+		testHeader(t, r, "X-Transport", "custom")
+		fmt.Fprintln(w, `{"info":{"username":"naber"}, "status":"OK"}`)
+	})
+
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r.Header.Set("X-Transport", "custom")
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	cl, err := New(
+		WithBaseURL(server.URL),
+		WithTransport(transport),
+		WithToken("t0ken"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if http.DefaultClient.Transport != nil {
+		t.Error("default HTTP client is modified")
+	}
+
+	info, err := cl.Account.Info(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Username != "naber" {
+		t.Errorf("got: %v, want: naber", info.Username)
+	}
+}
@@ -0,0 +1,302 @@
+package putio
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+// rootID is the ID of the user's root folder.
+const rootID = 0
+
+// maxPathDepth guards PathOf against parent loops.
+const maxPathDepth = 1024
+
+// PathCache caches the mapping between file IDs and their paths for
+// LookupPath, MkdirAll and PathOf. Entries are invalidated when files are
+// moved, renamed or deleted through the FilesService the cache is attached
+// to; changes made by other clients are not noticed. It is safe for
+// concurrent use.
+type PathCache struct {
+	mu     sync.RWMutex
+	byPath map[string]int64
+	byID   map[int64]string
+}
+
+// NewPathCache returns an empty PathCache.
+func NewPathCache() *PathCache {
+	return &PathCache{
+		byPath: make(map[string]int64),
+		byID:   make(map[int64]string),
+	}
+}
+
+// ID returns the cached ID of the file at p.
+func (c *PathCache) ID(p string) (int64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.byPath[p]
+	return id, ok
+}
+
+// Path returns the cached path of the file with the given ID.
+func (c *PathCache) Path(id int64) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p, ok := c.byID[id]
+	return p, ok
+}
+
+// Set records that the file with the given ID is at p.
+func (c *PathCache) Set(id int64, p string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.byID[id]; ok {
+		delete(c.byPath, old)
+	}
+	c.byPath[p] = id
+	c.byID[id] = p
+}
+
+// Invalidate removes the files with the given IDs and everything below them
+// from the cache.
+func (c *PathCache) Invalidate(ids ...int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		p, ok := c.byID[id]
+		if !ok {
+			continue
+		}
+		for q, qid := range c.byPath {
+			if q == p || strings.HasPrefix(q, p+"/") {
+				delete(c.byPath, q)
+				delete(c.byID, qid)
+			}
+		}
+	}
+}
+
+// Clear removes all entries.
+func (c *PathCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byPath = make(map[string]int64)
+	c.byID = make(map[int64]string)
+}
+
+// LookupPath returns the file at the given slash separated path, relative to
+// the user's root folder. A path that does not exist yields an error matching
+// ErrNotFound.
+func (f *FilesService) LookupPath(ctx context.Context, p string) (File, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.LookupPath")
+	defer span.End()
+
+	p = cleanPath(p)
+	if id, ok := f.cachedID(p); ok {
+		file, err := f.Get(ctx, id)
+		if err == nil {
+			return file, nil
+		}
+		// stale entry, resolve the path again
+		f.invalidate(id)
+	}
+
+	parent, rest, err := f.lookupCached(ctx, p)
+	if err != nil {
+		return File{}, err
+	}
+	return f.resolve(ctx, parent, rest, p)
+}
+
+// MkdirAll creates the folder at the given path along with any missing
+// parents and returns it. Existing folders are left untouched.
+func (f *FilesService) MkdirAll(ctx context.Context, p string) (File, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.MkdirAll")
+	defer span.End()
+
+	p = cleanPath(p)
+	parent, rest, err := f.lookupCached(ctx, p)
+	if err != nil {
+		return File{}, err
+	}
+
+	current := dirOf(p, len(rest))
+	for _, name := range rest {
+		if !parent.IsDir() && parent.ID != rootID {
+			return File{}, fmt.Errorf("%w: %q", ErrNotDir, current)
+		}
+		current = path.Join(current, name)
+		child, found, err := f.findChild(ctx, parent.ID, name)
+		if err != nil {
+			return File{}, err
+		}
+		if !found {
+			child, err = f.CreateFolder(ctx, name, parent.ID)
+			if err != nil {
+				return File{}, err
+			}
+			f.cacheSet(child.ID, current)
+		}
+		parent = child
+	}
+	if !parent.IsDir() && parent.ID != rootID {
+		return File{}, fmt.Errorf("%w: %q", ErrNotDir, p)
+	}
+	return parent, nil
+}
+
+// PathOf returns the slash separated path of the file with the given ID by
+// following its parents up to the root folder.
+func (f *FilesService) PathOf(ctx context.Context, id int64) (string, error) {
+	ctx, span := f.client.startSpan(ctx, "FilesService.PathOf", attrFileID.Int64(id))
+	defer span.End()
+
+	var names []string
+	var ids []int64
+	prefix := "/"
+	for current := id; current != rootID; {
+		if p, ok := f.cachedPath(current); ok {
+			prefix = p
+			break
+		}
+		if len(names) == maxPathDepth {
+			return "", fmt.Errorf("%w: path of %d is too deep", ErrUnexpected, id)
+		}
+		file, err := f.Get(ctx, current)
+		if err != nil {
+			return "", err
+		}
+		names = append(names, file.Name)
+		ids = append(ids, file.ID)
+		current = file.ParentID
+	}
+
+	// names are collected from the file up to the root
+	p := prefix
+	for i := len(names) - 1; i >= 0; i-- {
+		p = path.Join(p, names[i])
+		f.cacheSet(ids[i], p)
+	}
+	return p, nil
+}
+
+// lookupCached resolves the longest cached prefix of p. It returns the file at
+// that prefix and the remaining path elements.
+func (f *FilesService) lookupCached(ctx context.Context, p string) (File, []string, error) {
+	elems := splitPath(p)
+	for i := len(elems); i > 0; i-- {
+		id, ok := f.cachedID("/" + strings.Join(elems[:i], "/"))
+		if !ok {
+			continue
+		}
+		file, err := f.Get(ctx, id)
+		if err != nil {
+			// stale entry, resolve the whole path again
+			f.invalidate(id)
+			break
+		}
+		return file, elems[i:], nil
+	}
+	if len(elems) > 0 {
+		// the root folder is only listed, no need to fetch it
+		return File{ID: rootID, ContentType: "application/x-directory"}, elems, nil
+	}
+	root, err := f.Get(ctx, rootID)
+	if err != nil {
+		return File{}, nil, err
+	}
+	return root, nil, nil
+}
+
+// resolve follows the path elements rest down from parent. p is the whole
+// path, used in errors.
+func (f *FilesService) resolve(ctx context.Context, parent File, rest []string, p string) (File, error) {
+	for _, name := range rest {
+		if !parent.IsDir() && parent.ID != rootID {
+			return File{}, fmt.Errorf("%w: %q", ErrNotDir, p)
+		}
+		child, found, err := f.findChild(ctx, parent.ID, name)
+		if err != nil {
+			return File{}, err
+		}
+		if !found {
+			return File{}, fmt.Errorf("%w: %q", ErrNotFound, p)
+		}
+		parent = child
+	}
+	return parent, nil
+}
+
+// findChild looks for a child of the given folder by name.
+func (f *FilesService) findChild(ctx context.Context, parentID int64, name string) (File, bool, error) {
+	parentPath, _ := f.cachedPath(parentID)
+	if parentID == rootID {
+		parentPath = "/"
+	}
+
+	it := f.Iterate(ctx, parentID, nil)
+	for it.Next() {
+		file := it.File()
+		if file.Name == name {
+			if parentPath != "" {
+				f.cacheSet(file.ID, path.Join(parentPath, name))
+			}
+			return file, true, nil
+		}
+	}
+	return File{}, false, it.Err()
+}
+
+func (f *FilesService) cachedID(p string) (int64, bool) {
+	if f.PathCache == nil {
+		return 0, false
+	}
+	if p == "/" {
+		return rootID, true
+	}
+	return f.PathCache.ID(p)
+}
+
+func (f *FilesService) cachedPath(id int64) (string, bool) {
+	if f.PathCache == nil {
+		return "", false
+	}
+	if id == rootID {
+		return "/", true
+	}
+	return f.PathCache.Path(id)
+}
+
+func (f *FilesService) cacheSet(id int64, p string) {
+	if f.PathCache != nil {
+		f.PathCache.Set(id, p)
+	}
+}
+
+func (f *FilesService) invalidate(ids ...int64) {
+	if f.PathCache != nil {
+		f.PathCache.Invalidate(ids...)
+	}
+}
+
+// cleanPath returns p as a clean absolute path.
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// splitPath returns the elements of a clean absolute path.
+func splitPath(p string) []string {
+	if p == "/" {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(p, "/"), "/")
+}
+
+// dirOf returns p without its last n elements.
+func dirOf(p string, n int) string {
+	elems := splitPath(p)
+	return "/" + strings.Join(elems[:len(elems)-n], "/")
+}
@@ -0,0 +1,316 @@
+package putio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTree serves a put.io file tree from memory. Files are given as paths;
+// names ending with a slash are folders.
+type fakeTree struct {
+	mu       sync.Mutex
+	files    map[int64]*File
+	nextID   int64
+	requests map[string]int
+}
+
+func newFakeTree(paths ...string) *fakeTree {
+	tree := &fakeTree{
+		files: map[int64]*File{
+			0: {ID: 0, Name: "Your Files", ContentType: "application/x-directory", ParentID: -1},
+		},
+		nextID:   1,
+		requests: make(map[string]int),
+	}
+	for _, p := range paths {
+		parent := int64(0)
+		elems := strings.Split(strings.Trim(p, "/"), "/")
+		for i, name := range elems {
+			dir := i < len(elems)-1 || strings.HasSuffix(p, "/")
+			child, ok := tree.child(parent, name)
+			if !ok {
+				child = tree.add(name, parent, dir)
+			}
+			parent = child.ID
+		}
+	}
+	return tree
+}
+
+func (t *fakeTree) add(name string, parent int64, dir bool) *File {
+	f := &File{ID: t.nextID, Name: name, ParentID: parent, ContentType: "text/plain", FileType: FileTypeText}
+	if dir {
+		f.ContentType = "application/x-directory"
+		f.FileType = FileTypeFolder
+	} else {
+		f.Size = int64(len(name))
+		f.CRC32 = fmt.Sprintf("%08x", f.ID)
+	}
+	t.files[f.ID] = f
+	t.nextID++
+	return f
+}
+
+func (t *fakeTree) child(parent int64, name string) (*File, bool) {
+	for _, f := range t.children(parent) {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// children returns the children of parent ordered by ID.
+func (t *fakeTree) children(parent int64) []*File {
+	var files []*File
+	for id := int64(1); id < t.nextID; id++ {
+		if f, ok := t.files[id]; ok && f.ParentID == parent {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func (t *fakeTree) count(endpoint string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.requests[endpoint]
+}
+
+func (t *fakeTree) register(mux *http.ServeMux) {
+	mux.HandleFunc("/v2/files/", func(w http.ResponseWriter, r *http.Request) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		endpoint := strings.TrimPrefix(r.URL.Path, "/v2/files/")
+		if _, err := strconv.ParseInt(endpoint, 10, 64); err == nil {
+			t.requests["get"]++
+		} else {
+			t.requests[endpoint]++
+		}
+
+		if id, ok := strings.CutSuffix(endpoint, "/url"); ok {
+			fmt.Fprintf(w, `{"url":"http://%s/download/%s"}`, r.Host, id)
+			return
+		}
+
+		switch endpoint {
+		case "list":
+			id, _ := strconv.ParseInt(r.URL.Query().Get("parent_id"), 10, 64)
+			parent, ok := t.files[id]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			children := []File{}
+			for _, f := range t.children(id) {
+				children = append(children, *f)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"files": children, "parent": parent})
+		case "create-folder":
+			parent, _ := strconv.ParseInt(r.FormValue("parent_id"), 10, 64)
+			f := t.add(r.FormValue("name"), parent, true)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"file": f})
+		case "rename":
+			id, _ := strconv.ParseInt(r.FormValue("file_id"), 10, 64)
+			t.files[id].Name = r.FormValue("name")
+			fmt.Fprintln(w, `{"status":"OK"}`)
+		case "move":
+			parent, _ := strconv.ParseInt(r.FormValue("parent_id"), 10, 64)
+			for _, s := range strings.Split(r.FormValue("file_ids"), ",") {
+				id, _ := strconv.ParseInt(s, 10, 64)
+				t.files[id].ParentID = parent
+			}
+			fmt.Fprintln(w, `{"status":"OK"}`)
+		case "delete":
+			for _, s := range strings.Split(r.FormValue("file_ids"), ",") {
+				id, _ := strconv.ParseInt(s, 10, 64)
+				delete(t.files, id)
+			}
+			fmt.Fprintln(w, `{"status":"OK"}`)
+		default:
+			id, _ := strconv.ParseInt(endpoint, 10, 64)
+			f, ok := t.files[id]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"file": f})
+		}
+	})
+
+	// the contents of a file are its name
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		t.mu.Lock()
+		t.requests["download"]++
+		id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/download/"), 10, 64)
+		f, ok := t.files[id]
+		t.mu.Unlock()
+		if !ok || f.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, f.Name, time.Time{}, strings.NewReader(f.Name))
+	})
+}
+
+func TestFiles_LookupPath(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree("/Movies/2024/foo.mkv", "/Movies/2023/", "/notes.txt")
+	tree.register(mux)
+
+	file, err := client.Files.LookupPath(context.Background(), "/Movies/2024/foo.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "foo.mkv" {
+		t.Errorf("got: %v, want: foo.mkv", file.Name)
+	}
+
+	root, err := client.Files.LookupPath(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.ID != 0 {
+		t.Errorf("got: %v, want: 0", root.ID)
+	}
+
+	_, err = client.Files.LookupPath(context.Background(), "/Movies/2025")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got: %v, want: %v", err, ErrNotFound)
+	}
+
+	_, err = client.Files.LookupPath(context.Background(), "/notes.txt/foo")
+	if !errors.Is(err, ErrNotDir) {
+		t.Errorf("got: %v, want: %v", err, ErrNotDir)
+	}
+}
+
+func TestFiles_MkdirAll(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree("/Movies/2024/")
+	tree.register(mux)
+
+	dir, err := client.Files.MkdirAll(context.Background(), "Movies/2024/Action/Old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir.Name != "Old" || !dir.IsDir() {
+		t.Errorf("got: %v", dir)
+	}
+	if n := tree.count("create-folder"); n != 2 {
+		t.Errorf("got: %v folders created, want: 2", n)
+	}
+
+	again, err := client.Files.MkdirAll(context.Background(), "/Movies/2024/Action/Old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != dir.ID || tree.count("create-folder") != 2 {
+		t.Errorf("existing folder is created again")
+	}
+}
+
+func TestFiles_PathOf(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree("/Movies/2024/foo.mkv")
+	tree.register(mux)
+
+	foo, _ := tree.child(2, "foo.mkv")
+	p, err := client.Files.PathOf(context.Background(), foo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != "/Movies/2024/foo.mkv" {
+		t.Errorf("got: %v, want: /Movies/2024/foo.mkv", p)
+	}
+
+	p, err = client.Files.PathOf(context.Background(), 0)
+	if err != nil || p != "/" {
+		t.Errorf("got: %v %v, want: /", p, err)
+	}
+}
+
+func TestFiles_PathCache(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree("/Movies/2024/foo.mkv", "/Archive/")
+	tree.register(mux)
+	client.Files.PathCache = NewPathCache()
+	ctx := context.Background()
+
+	foo, err := client.Files.LookupPath(ctx, "/Movies/2024/foo.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lists := tree.count("list")
+
+	// cached lookups do not list folders
+	p, err := client.Files.PathOf(ctx, foo.ID)
+	if err != nil || p != "/Movies/2024/foo.mkv" {
+		t.Errorf("got: %v %v", p, err)
+	}
+	_, err = client.Files.LookupPath(ctx, "/Movies/2024/foo.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.count("list") != lists {
+		t.Errorf("cached path is listed again")
+	}
+
+	// a file replaced by another client is looked up again
+	tree.mu.Lock()
+	delete(tree.files, foo.ID)
+	replaced := tree.add("foo.mkv", foo.ParentID, false)
+	tree.mu.Unlock()
+	got, err := client.Files.LookupPath(ctx, "/Movies/2024/foo.mkv")
+	if err != nil || got.ID != replaced.ID {
+		t.Errorf("got: %v %v, want: %v", got.ID, err, replaced.ID)
+	}
+	if id, _ := client.Files.PathCache.ID("/Movies/2024/foo.mkv"); id != replaced.ID {
+		t.Errorf("got: %v cached, want: %v", id, replaced.ID)
+	}
+	foo = *replaced
+
+	// renaming the parent invalidates the file below it
+	year, _ := tree.child(1, "2024")
+	err = client.Files.Rename(ctx, year.ID, "2025")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Files.LookupPath(ctx, "/Movies/2024/foo.mkv")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got: %v, want: %v", err, ErrNotFound)
+	}
+
+	// moving updates the path
+	archive, _ := tree.child(0, "Archive")
+	err = client.Files.Move(ctx, archive.ID, foo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err = client.Files.PathOf(ctx, foo.ID)
+	if err != nil || p != "/Archive/foo.mkv" {
+		t.Errorf("got: %v %v, want: /Archive/foo.mkv", p, err)
+	}
+
+	// deleting removes the entry
+	err = client.Files.Delete(ctx, foo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := client.Files.PathCache.Path(foo.ID); ok {
+		t.Error("deleted file is still cached")
+	}
+}
@@ -0,0 +1,188 @@
+package putio
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// progressInterval is the shortest time between two progress reports.
+	progressInterval = 100 * time.Millisecond
+
+	// rateSmoothing is the weight of the latest sample in Progress.Rate.
+	rateSmoothing = 0.3
+)
+
+// Progress is a snapshot of a transfer.
+type Progress struct {
+	// Done is the number of bytes transferred, including the bytes of
+	// resumed transfers.
+	Done int64
+	// Total is the size of the transfer, or -1 if it is unknown.
+	Total int64
+	// Rate is the recent throughput in bytes per second.
+	Rate float64
+	// AvgRate is the throughput since the transfer started, in bytes per
+	// second. Resumed bytes are not counted.
+	AvgRate float64
+	// Elapsed is the time since the transfer started.
+	Elapsed time.Duration
+	// ETA is the estimated time left, or zero if it is unknown.
+	ETA time.Duration
+}
+
+// Percent returns Done as a percentage of Total, or 0 if Total is unknown.
+func (p Progress) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return float64(p.Done) / float64(p.Total) * 100
+}
+
+// ProgressFunc receives progress reports. Reports are made at most every
+// 100 ms, and once more when the transfer ends. A ProgressFunc is never
+// called concurrently for the same transfer and should return quickly.
+type ProgressFunc func(Progress)
+
+// ProgressChan returns a ProgressFunc that sends reports to ch. Reports are
+// dropped while ch is full, so a slow receiver never stalls the transfer.
+func ProgressChan(ch chan<- Progress) ProgressFunc {
+	return func(p Progress) {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+// ProgressReader reports the bytes read through it.
+type ProgressReader struct {
+	r       io.Reader
+	tracker *progressTracker
+}
+
+// NewProgressReader returns a reader that reads from r and reports progress
+// to fn. Total is the number of bytes expected, or -1 if unknown. Use it to
+// observe UploadService.SendFile, FilesService.Upload or a FileReader.
+func NewProgressReader(r io.Reader, total int64, fn ProgressFunc) *ProgressReader {
+	return &ProgressReader{r: r, tracker: newProgressTracker(fn, total, 0)}
+}
+
+func (r *ProgressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.tracker.add(int64(n))
+	if err != nil {
+		r.tracker.finish()
+	}
+	return n, err // nolint:wrapcheck
+}
+
+// trackedReader adds the bytes read through it to a tracker.
+type trackedReader struct {
+	r       io.Reader
+	tracker *progressTracker
+}
+
+func (r *trackedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.tracker.add(int64(n))
+	return n, err // nolint:wrapcheck
+}
+
+// progressTracker computes progress reports from byte counts and throttles
+// them. It is safe for concurrent use.
+type progressTracker struct {
+	mu    sync.Mutex
+	fn    ProgressFunc
+	total int64
+	done  int64
+	// initial is the number of bytes done before the start
+	initial int64
+	start   time.Time
+
+	lastReport time.Time
+	lastDone   int64
+	rate       float64
+	finished   bool
+}
+
+// newProgressTracker returns a tracker of a transfer of total bytes of which
+// done are already transferred. A nil fn disables reporting.
+func newProgressTracker(fn ProgressFunc, total, done int64) *progressTracker {
+	return &progressTracker{fn: fn, total: total, done: done, initial: done, start: time.Now(), lastDone: done}
+}
+
+// add adds n transferred bytes.
+func (t *progressTracker) add(n int64) {
+	if t == nil || t.fn == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done += n
+	t.report(false)
+}
+
+// set sets the number of transferred bytes, for example after bytes sent
+// were lost.
+func (t *progressTracker) set(done int64) {
+	if t == nil || t.fn == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done = done
+	if t.lastDone > done {
+		t.lastDone = done
+	}
+	t.report(false)
+}
+
+// finish makes the final report.
+func (t *progressTracker) finish() {
+	if t == nil || t.fn == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.finished {
+		t.report(true)
+		t.finished = true
+	}
+}
+
+// report calls fn if enough time has passed since the last report. It must
+// be called with mu held.
+func (t *progressTracker) report(force bool) {
+	now := time.Now()
+	since := now.Sub(t.lastReport)
+	if !force && !t.lastReport.IsZero() && since < progressInterval {
+		return
+	}
+
+	if !t.lastReport.IsZero() && since > 0 {
+		sample := float64(t.done-t.lastDone) / since.Seconds()
+		if t.rate == 0 {
+			t.rate = sample
+		} else {
+			t.rate = rateSmoothing*sample + (1-rateSmoothing)*t.rate
+		}
+	}
+	t.lastReport = now
+	t.lastDone = t.done
+
+	p := Progress{
+		Done:    t.done,
+		Total:   t.total,
+		Rate:    t.rate,
+		Elapsed: now.Sub(t.start),
+	}
+	if p.Elapsed > 0 {
+		p.AvgRate = float64(t.done-t.initial) / p.Elapsed.Seconds()
+	}
+	if p.Rate > 0 && p.Total > p.Done {
+		p.ETA = time.Duration(float64(p.Total-p.Done) / p.Rate * float64(time.Second))
+	}
+	t.fn(p)
+}
@@ -0,0 +1,95 @@
+package putio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressReader(t *testing.T) {
+	var reports []Progress
+	r := NewProgressReader(strings.NewReader(readerContent), int64(len(readerContent)), func(p Progress) {
+		reports = append(reports, p)
+	})
+	_, err := io.Copy(io.Discard, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first read and the end are reported, the rest is throttled
+	if len(reports) != 2 {
+		t.Fatalf("got: %v reports, want: 2", len(reports))
+	}
+	last := reports[len(reports)-1]
+	if last.Done != int64(len(readerContent)) || last.Percent() != 100 || last.ETA != 0 {
+		t.Errorf("got: %+v", last)
+	}
+}
+
+func TestProgressTracker_rates(t *testing.T) {
+	var last Progress
+	tracker := newProgressTracker(func(p Progress) { last = p }, 1000, 100)
+	tracker.start = time.Now().Add(-2 * time.Second)
+	tracker.lastReport = time.Now().Add(-time.Second)
+
+	tracker.add(200)
+	if last.Done != 300 || last.Total != 1000 {
+		t.Fatalf("got: %+v", last)
+	}
+	// 200 bytes in about a second
+	if last.Rate < 150 || last.Rate > 210 {
+		t.Errorf("got rate: %v, want: about 200", last.Rate)
+	}
+	// resumed bytes do not count
+	if last.AvgRate < 80 || last.AvgRate > 110 {
+		t.Errorf("got average rate: %v, want: about 100", last.AvgRate)
+	}
+	if last.ETA < 3*time.Second || last.ETA > 5*time.Second {
+		t.Errorf("got ETA: %v, want: about 3.5s", last.ETA)
+	}
+}
+
+func TestProgressChan(t *testing.T) {
+	ch := make(chan Progress, 1)
+	fn := ProgressChan(ch)
+	fn(Progress{Done: 1})
+	// must not block on a full channel
+	fn(Progress{Done: 2})
+	if p := <-ch; p.Done != 1 {
+		t.Errorf("got: %v, want: 1", p.Done)
+	}
+}
+
+func TestUploader_Progress(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &tusServer{fail: func(patch int) int {
+		if patch == 2 {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}}
+	server.register(t)
+
+	ch := make(chan Progress, 100)
+	u := NewUploader(client)
+	u.ChunkSize = 8
+	u.RetryPolicy = fastRetryPolicy()
+	u.Progress = ProgressChan(ch)
+	_, err := u.Upload(context.Background(), strings.NewReader(readerContent), "file", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(ch)
+
+	var last Progress
+	for p := range ch {
+		last = p
+	}
+	if last.Done != int64(len(readerContent)) || last.Total != int64(len(readerContent)) {
+		t.Errorf("got: %+v", last)
+	}
+}
@@ -0,0 +1,124 @@
+package putio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of requests made by Client. Wait blocks until a
+// request is allowed to proceed or ctx is done. *rate.Limiter from
+// golang.org/x/time/rate satisfies this interface.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a RateLimiter that allows events at Rate per second with
+// bursts of up to Burst events. It is safe for concurrent use and its limits
+// can be changed while it is in use.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a TokenBucket that allows r events per second with
+// bursts of up to burst events. The bucket starts full. A rate of zero or
+// less disables limiting.
+func NewTokenBucket(r float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   r,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Rate returns the current rate in events per second.
+func (b *TokenBucket) Rate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rate
+}
+
+// Burst returns the current burst size.
+func (b *TokenBucket) Burst() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.burst
+}
+
+// SetRate changes the rate. A rate of zero or less disables limiting.
+func (b *TokenBucket) SetRate(r float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	b.rate = r
+}
+
+// SetBurst changes the burst size.
+func (b *TokenBucket) SetBurst(n int) {
+	if n < 1 {
+		n = 1
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	b.burst = n
+	b.tokens = math.Min(b.tokens, float64(n))
+}
+
+// Wait blocks until one event is allowed or ctx is done.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	return b.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n events are allowed or ctx is done. It returns an error
+// matching ErrBurstExceeded if n exceeds the burst size.
+func (b *TokenBucket) WaitN(ctx context.Context, n int) error {
+	b.mu.Lock()
+	if n > b.burst {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: %d exceeds burst %d", ErrBurstExceeded, n, b.burst)
+	}
+	if b.rate <= 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	b.advance(now)
+	b.tokens -= float64(n)
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		// give back the reserved tokens
+		b.mu.Lock()
+		b.tokens = math.Min(b.tokens+float64(n), float64(b.burst))
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// advance refills the bucket for the time passed since the last call. b.mu
+// must be held.
+func (b *TokenBucket) advance(now time.Time) {
+	if b.rate > 0 {
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = math.Min(b.tokens+elapsed*b.rate, float64(b.burst))
+	} else {
+		b.tokens = float64(b.burst)
+	}
+	b.last = now
+}
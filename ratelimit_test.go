@@ -0,0 +1,119 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingLimiter struct {
+	n int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.n++
+	return ctx.Err()
+}
+
+func TestTokenBucket_Wait(t *testing.T) {
+	b := NewTokenBucket(100, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := b.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// two events are served from the burst, two more take 10ms each.
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("got: %v, want at least 15ms", elapsed)
+	}
+}
+
+func TestTokenBucket_WaitCanceled(t *testing.T) {
+	b := NewTokenBucket(0.001, 1)
+	_ = b.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := b.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got: %v, want: %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTokenBucket_WaitNExceedsBurst(t *testing.T) {
+	b := NewTokenBucket(10, 5)
+	err := b.WaitN(context.Background(), 6)
+	if !errors.Is(err, ErrBurstExceeded) {
+		t.Errorf("got: %v, want: %v", err, ErrBurstExceeded)
+	}
+}
+
+func TestTokenBucket_SetRate(t *testing.T) {
+	b := NewTokenBucket(0.001, 1)
+	_ = b.Wait(context.Background())
+	b.SetRate(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.Wait(ctx); err != nil {
+		t.Errorf("unlimited bucket blocked: %v", err)
+	}
+}
+
+func TestClient_RateLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	api := &countingLimiter{}
+	upload := &countingLimiter{}
+	client.RateLimiter = api
+	client.UploadRateLimiter = upload
+
+	mux.HandleFunc("/v2/account/info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"info":{}, "status":"OK"}`)
+	})
+	mux.HandleFunc("/v2/files/upload", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"file":{}, "status":"OK"}`)
+	})
+
+	_, err := client.Account.Info(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Files.Upload(context.Background(), strings.NewReader("x"), "x.txt", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.n != 1 || upload.n != 1 {
+		t.Errorf("got: api=%d upload=%d, want: api=1 upload=1", api.n, upload.n)
+	}
+}
+
+func TestClient_RateLimiterCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RateLimiter = NewTokenBucket(0.001, 1)
+	_ = client.RateLimiter.Wait(context.Background())
+
+	var calls int
+	mux.HandleFunc("/v2/account/info", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.Account.Info(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got: %v, want: %v", err, context.DeadlineExceeded)
+	}
+	if calls != 0 {
+		t.Errorf("got: %v calls, want: 0", calls)
+	}
+}
@@ -0,0 +1,293 @@
+package putio
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+)
+
+const (
+	// defaultReadAhead is used when ReaderOptions.ReadAhead is zero.
+	defaultReadAhead = 256 << 10
+
+	// defaultMaxReconnects is used when ReaderOptions.MaxReconnects is zero.
+	defaultMaxReconnects = 3
+)
+
+// ReaderOptions configure a FileReader.
+type ReaderOptions struct {
+	// ReadAhead is the size of the buffer filled ahead of Read. Seeking
+	// forward within the buffer does not make a new request. The default is
+	// 256 KiB; a negative value disables the buffer.
+	ReadAhead int
+
+	// MaxReconnects is the number of times a dropped connection is
+	// reopened at the current offset before the error is returned. The count
+	// is reset whenever data is read. The default is 3; a negative value
+	// disables reconnecting.
+	MaxReconnects int
+
+	// UseTunnel downloads through put.io's tunnel, see FilesService.URL.
+	UseTunnel bool
+}
+
+// FileReader reads a file over HTTP Range requests. It implements
+// io.ReadSeekCloser and io.ReaderAt. Read and Seek share an offset and must
+// not be called concurrently; ReadAt is independent of them and safe for
+// concurrent use.
+type FileReader struct {
+	files *FilesService
+	ctx   context.Context
+	file  File
+	url   string
+	opts  ReaderOptions
+
+	offset int64
+	body   io.ReadCloser
+	// rd reads body, through buf when read-ahead is enabled
+	rd     io.Reader
+	buf    *bufio.Reader
+	closed bool
+}
+
+// Open opens the file with the given ID for reading. Requests are made with
+// ctx until the reader is closed.
+func (f *FilesService) Open(ctx context.Context, id int64) (*FileReader, error) {
+	return f.OpenWithOptions(ctx, id, nil)
+}
+
+// OpenWithOptions is like Open with options. Nil opts is equivalent to the
+// zero ReaderOptions.
+func (f *FilesService) OpenWithOptions(ctx context.Context, id int64, opts *ReaderOptions) (*FileReader, error) {
+	spanCtx, span := f.client.startSpan(ctx, "FilesService.Open", attrFileID.Int64(id))
+	defer span.End()
+
+	file, err := f.Get(spanCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	return f.openFile(ctx, file, opts)
+}
+
+// openFile returns a reader of file.
+func (f *FilesService) openFile(ctx context.Context, file File, opts *ReaderOptions) (*FileReader, error) {
+	if file.IsDir() {
+		return nil, fmt.Errorf("%w: %v", ErrIsDir, file.ID)
+	}
+	r := &FileReader{files: f, ctx: ctx, file: file}
+	if opts != nil {
+		r.opts = *opts
+	}
+
+	var err error
+	r.url, err = f.URL(ctx, file.ID, r.opts.UseTunnel)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Size returns the size of the file.
+func (r *FileReader) Size() int64 {
+	return r.file.Size
+}
+
+// Read reads from the current offset, reconnecting if the connection drops.
+func (r *FileReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, fs.ErrClosed
+	}
+	if r.offset >= r.file.Size {
+		return 0, io.EOF
+	}
+
+	for attempt := 1; ; attempt++ {
+		if r.body == nil {
+			err := r.connect()
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := r.rd.Read(p)
+		r.offset += int64(n)
+		if err == nil || r.offset >= r.file.Size {
+			return n, err
+		}
+
+		r.closeBody()
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		if n > 0 {
+			// reconnect on the next read
+			return n, nil
+		}
+		err = r.backoff(err, attempt)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// ReadAt reads len(p) bytes at off with a request of its own.
+func (r *FileReader) ReadAt(p []byte, off int64) (int, error) {
+	if r.closed {
+		return 0, fs.ErrClosed
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("%w: negative offset", ErrUnexpected)
+	}
+	if off >= r.file.Size {
+		return 0, io.EOF
+	}
+	want := len(p)
+	if rest := r.file.Size - off; int64(want) > rest {
+		want = int(rest)
+	}
+
+	var n int
+	for attempt := 1; n < want; attempt++ {
+		body, err := r.open(off+int64(n), int64(want-n))
+		if err == nil {
+			var m int
+			m, err = io.ReadFull(body, p[n:want])
+			_ = body.Close()
+			n += m
+			if m > 0 {
+				attempt = 0
+			}
+		}
+		if err != nil && n < want {
+			err = r.backoff(err, attempt)
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Seek sets the offset of the next Read. Seeking forward within the
+// read-ahead buffer reuses the open connection.
+func (r *FileReader) Seek(offset int64, whence int) (int64, error) {
+	if r.closed {
+		return 0, fs.ErrClosed
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.file.Size
+	default:
+		return 0, fmt.Errorf("%w: invalid whence %d", ErrUnexpected, whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("%w: negative offset", ErrUnexpected)
+	}
+
+	skip := offset - r.offset
+	switch {
+	case skip == 0:
+	case r.buf != nil && r.body != nil && skip > 0 && skip <= int64(r.buf.Buffered()):
+		_, _ = r.buf.Discard(int(skip))
+	default:
+		r.closeBody()
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+// Close closes the open connection, if any.
+func (r *FileReader) Close() error {
+	if r.closed {
+		return fs.ErrClosed
+	}
+	r.closeBody()
+	r.closed = true
+	return nil
+}
+
+// connect opens a connection at the current offset.
+func (r *FileReader) connect() error {
+	body, err := r.open(r.offset, -1)
+	if err != nil {
+		return err
+	}
+	r.body = body
+	r.rd = body
+
+	size := r.opts.ReadAhead
+	if size == 0 {
+		size = defaultReadAhead
+	}
+	if size > 0 {
+		if r.buf == nil {
+			r.buf = bufio.NewReaderSize(body, size)
+		} else {
+			r.buf.Reset(body)
+		}
+		r.rd = r.buf
+	}
+	return nil
+}
+
+func (r *FileReader) closeBody() {
+	if r.body != nil {
+		_ = r.body.Close()
+		r.body = nil
+	}
+}
+
+// open requests n bytes of the file starting at off. Negative n requests the
+// rest of the file.
+func (r *FileReader) open(off, n int64) (io.ReadCloser, error) {
+	req, err := r.files.client.NewRequest(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", off))
+	} else {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+n-1))
+	}
+
+	resp, err := r.files.client.Do(req, nil) // nolint:bodyclose
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent && off > 0 {
+		_ = resp.Body.Close()
+		return nil, ErrRangeNotSupported
+	}
+	return resp.Body, nil
+}
+
+// backoff waits before the given reconnect attempt after err. It returns err
+// if the connection must not be reopened.
+func (r *FileReader) backoff(err error, attempt int) error {
+	limit := r.opts.MaxReconnects
+	if limit == 0 {
+		limit = defaultMaxReconnects
+	}
+
+	// HTTP errors are already retried by the client
+	var er *ErrorResponse
+	if attempt > limit || errors.As(err, &er) || errors.Is(err, ErrRangeNotSupported) || r.ctx.Err() != nil {
+		return err
+	}
+	if policy := r.files.client.RetryPolicy; policy != nil {
+		if sleep(r.ctx, policy.Backoff(attempt)) != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const readerContent = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// setupReader serves readerContent as file 1. Responses to requests starting
+// at an offset in drop are cut after 4 bytes, once per offset. It returns the
+// ranges requested.
+func setupReader(t *testing.T, drop ...int) func() []string {
+	var mu sync.Mutex
+	var ranges []string
+	dropped := make(map[int]bool)
+
+	mux.HandleFunc("/v2/files/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"file":{"id":1,"name":"file","size":%d,"crc32":"%08x"}}`,
+			len(readerContent), crc32.ChecksumIEEE([]byte(readerContent)))
+	})
+	mux.HandleFunc("/v2/files/1/url", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"url":"http://%s/download/1"}`, r.Host)
+	})
+	mux.HandleFunc("/download/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		rng := r.Header.Get("Range")
+		start, _ := strconv.Atoi(strings.TrimPrefix(strings.SplitN(rng, "-", 2)[0], "bytes="))
+
+		mu.Lock()
+		ranges = append(ranges, rng)
+		drop := contains(drop, start) && !dropped[start]
+		dropped[start] = true
+		mu.Unlock()
+
+		if drop {
+			// claim the whole file but send 4 bytes only
+			w.Header().Set("Content-Length", strconv.Itoa(len(readerContent)-start))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(readerContent[start : start+4]))
+			return
+		}
+		http.ServeContent(w, r, "file", time.Time{}, strings.NewReader(readerContent))
+	})
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), ranges...)
+	}
+}
+
+func contains(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFiles_Open(t *testing.T) {
+	setup()
+	defer teardown()
+	ranges := setupReader(t)
+
+	r, err := client.Files.OpenWithOptions(context.Background(), 1, &ReaderOptions{ReadAhead: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if r.Size() != int64(len(readerContent)) {
+		t.Errorf("got size: %v, want: %v", r.Size(), len(readerContent))
+	}
+
+	p := make([]byte, 4)
+	_, err = io.ReadFull(r, p)
+	if err != nil || string(p) != "0123" {
+		t.Fatalf("got: %q %v, want: 0123", p, err)
+	}
+
+	// within the read-ahead buffer
+	_, _ = r.Seek(8, io.SeekCurrent)
+	_, err = io.ReadFull(r, p)
+	if err != nil || string(p) != "cdef" {
+		t.Fatalf("got: %q %v, want: cdef", p, err)
+	}
+	if got := ranges(); len(got) != 1 {
+		t.Errorf("got: %v, want: 1 request", got)
+	}
+
+	// beyond the buffer
+	_, _ = r.Seek(-6, io.SeekEnd)
+	rest, err := io.ReadAll(r)
+	if err != nil || string(rest) != "uvwxyz" {
+		t.Errorf("got: %q %v, want: uvwxyz", rest, err)
+	}
+	if got := ranges(); fmt.Sprint(got) != "[bytes=0- bytes=30-]" {
+		t.Errorf("got: %v, want: [bytes=0- bytes=30-]", got)
+	}
+
+	_ = r.Close()
+	if _, err = r.Read(p); err == nil {
+		t.Error("read after close succeeded")
+	}
+}
+
+func TestFiles_Open_reconnect(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+	ranges := setupReader(t, 0, 4)
+
+	r, err := client.Files.OpenWithOptions(context.Background(), 1, &ReaderOptions{ReadAhead: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != readerContent {
+		t.Errorf("got: %q, want: %q", b, readerContent)
+	}
+	if got := ranges(); fmt.Sprint(got) != "[bytes=0- bytes=4- bytes=8-]" {
+		t.Errorf("got: %v, want: [bytes=0- bytes=4- bytes=8-]", got)
+	}
+}
+
+func TestFiles_Open_noReconnect(t *testing.T) {
+	setup()
+	defer teardown()
+	setupReader(t, 0)
+
+	r, err := client.Files.OpenWithOptions(context.Background(), 1, &ReaderOptions{ReadAhead: -1, MaxReconnects: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	_, err = io.ReadAll(r)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("got: %v, want: %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestFileReader_ReadAt(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+	ranges := setupReader(t, 10)
+
+	r, err := client.Files.Open(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	p := make([]byte, 8)
+	n, err := r.ReadAt(p, 10)
+	if err != nil || string(p[:n]) != "abcdefgh" {
+		t.Errorf("got: %q %v, want: abcdefgh", p[:n], err)
+	}
+	if got := ranges(); fmt.Sprint(got) != "[bytes=10-17 bytes=14-17]" {
+		t.Errorf("got: %v, want: [bytes=10-17 bytes=14-17]", got)
+	}
+
+	n, err = r.ReadAt(p, 32)
+	if !errors.Is(err, io.EOF) || string(p[:n]) != "wxyz" {
+		t.Errorf("got: %q %v, want: wxyz and EOF", p[:n], err)
+	}
+}
+
+// serveSlowly serves readerContent as file 2 in pieces of 6 bytes, waiting
+// gap before each piece. It returns the number of connections.
+func serveSlowly(gap time.Duration) func() int {
+	var mu sync.Mutex
+	var connections int
+	mux.HandleFunc("/v2/files/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"file":{"id":2,"name":"file","size":%d}}`, len(readerContent))
+	})
+	mux.HandleFunc("/v2/files/2/url", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"url":"http://%s/download/2"}`, r.Host)
+	})
+	mux.HandleFunc("/download/2", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections++
+		mu.Unlock()
+		start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.Header.Get("Range"), "bytes="), "-"))
+		w.Header().Set("Content-Length", strconv.Itoa(len(readerContent)-start))
+		w.WriteHeader(http.StatusPartialContent)
+		w.(http.Flusher).Flush()
+		for off := start; off < len(readerContent); off += 6 {
+			select {
+			case <-time.After(gap):
+			case <-r.Context().Done():
+				return
+			}
+			_, _ = w.Write([]byte(readerContent[off:min(off+6, len(readerContent))]))
+			w.(http.Flusher).Flush()
+		}
+	})
+	return func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return connections
+	}
+}
+
+func TestFileReader_longBody(t *testing.T) {
+	setup()
+	defer teardown()
+	connections := serveSlowly(30 * time.Millisecond)
+
+	// the body takes twice as long as the timeout, but never stalls
+	client.Timeout = 100 * time.Millisecond
+	r, err := client.Files.OpenWithOptions(context.Background(), 2, &ReaderOptions{ReadAhead: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != readerContent {
+		t.Errorf("got: %q, want: %q", b, readerContent)
+	}
+	if n := connections(); n != 1 {
+		t.Errorf("got: %v connections, want: 1", n)
+	}
+}
+
+func TestClient_Do_stalledBody(t *testing.T) {
+	setup()
+	defer teardown()
+	serveSlowly(200 * time.Millisecond)
+
+	client.Timeout = 50 * time.Millisecond
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/download/2", nil)
+	resp, err := client.Do(req, nil) // nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	_, err = io.ReadAll(resp.Body)
+	if err == nil {
+		t.Error("stalled body is read to the end")
+	}
+}
@@ -0,0 +1,232 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how Client.Do retries requests that failed with a
+// transport error or a retryable HTTP status code.
+//
+// Only idempotent requests (GET, HEAD, OPTIONS, PUT, DELETE) are retried
+// unless RetryNonIdempotent is set. Read only calls of the API made with
+// POST, such as the listing of further pages of a folder, count as
+// idempotent. A request body is replayed through http.Request.GetBody, which
+// is populated by NewRequest for *strings.Reader, *bytes.Reader and
+// *bytes.Buffer bodies. Requests with any other body are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the computed delay between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor the delay grows by after each attempt. Values
+	// less than 1 are treated as 2.
+	Multiplier float64
+
+	// Jitter is the fraction, between 0 and 1, of random spread applied to
+	// each delay.
+	Jitter float64
+
+	// RetryableStatus lists the HTTP status codes that are retried.
+	RetryableStatus []int
+
+	// RetryNonIdempotent enables retries for POST and PATCH requests.
+	RetryNonIdempotent bool
+
+	// MaxRetryAfter is the longest Retry-After delay the client is willing to
+	// wait. If the server asks for more, the error is returned to the caller
+	// instead. Zero means any delay is honoured.
+	MaxRetryAfter time.Duration
+
+	// ShouldRetry, if not nil, replaces the default decision of whether a
+	// response or transport error is retryable. It is not consulted for
+	// requests that cannot be retried safely.
+	ShouldRetry func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy returns the policy used by NewClient. It makes up to 3
+// attempts for idempotent requests on transport errors and on 429, 500, 502,
+// 503 and 504 responses. A server asking to wait more than 30 seconds with
+// Retry-After is not waited for; the error is returned instead.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:   3,
+		MinBackoff:    500 * time.Millisecond,
+		MaxBackoff:    30 * time.Second,
+		Multiplier:    2,
+		Jitter:        0.2,
+		MaxRetryAfter: 30 * time.Second,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// Backoff returns the delay to wait after the given failed attempt, which
+// starts from 1.
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	d := float64(p.MinBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (rand.Float64()*2 - 1) // nolint:gosec
+	}
+	if d < 0 {
+		return 0
+	}
+	return time.Duration(d)
+}
+
+// retry reports whether the failed attempt of r should be retried and how long
+// to wait before doing so.
+func (p *RetryPolicy) retry(r *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if r.Context().Err() != nil {
+		return 0, false
+	}
+	if !p.RetryNonIdempotent && !isIdempotent(r.Method) && !isReadOnly(r.Context()) {
+		return 0, false
+	}
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return 0, false
+	}
+
+	retryable := p.retryable
+	if p.ShouldRetry != nil {
+		retryable = p.ShouldRetry
+	}
+	if !retryable(resp, err) {
+		return 0, false
+	}
+
+	if d, ok := retryAfter(resp); ok {
+		if p.MaxRetryAfter > 0 && d > p.MaxRetryAfter {
+			return 0, false
+		}
+		return d, true
+	}
+	return p.Backoff(attempt), true
+}
+
+// retryable is the default classification used when ShouldRetry is nil.
+func (p *RetryPolicy) retryable(resp *http.Response, err error) bool {
+	var er *ErrorResponse
+	if errors.As(err, &er) {
+		resp = er.Response
+	} else if err != nil {
+		// transport errors, including per-attempt timeouts
+		return !errors.Is(err, context.Canceled)
+	}
+	if resp == nil {
+		return false
+	}
+	for _, code := range p.RetryableStatus {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// rewind returns a copy of r with a fresh body, ready to be sent again.
+func rewind(r *http.Request) (*http.Request, error) {
+	req := r.Clone(r.Context())
+	if r.Body == nil || r.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return nil, err // nolint:wrapcheck
+	}
+	req.Body = body
+	return req, nil
+}
+
+// readOnlyKey is the context key marking a request that changes nothing on
+// the server, whatever its method.
+type readOnlyKey struct{}
+
+// withReadOnly returns a context whose requests are safe to retry.
+func withReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+func isReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header of resp, which is either a number of
+// seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err() // nolint:wrapcheck
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err() // nolint:wrapcheck
+	case <-t.C:
+		return nil
+	}
+}
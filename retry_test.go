@@ -0,0 +1,188 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy() *RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.MinBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+func TestClient_Do_retryGet(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+
+	var calls int
+	mux.HandleFunc("/v2/retry", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, `{"status":"OK"}`)
+	})
+
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/v2/retry", nil)
+	_, err := client.Do(req, &struct{}{}) // nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("got: %v calls, want: 3", calls)
+	}
+}
+
+func TestClient_Do_retryGivesUp(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+
+	var calls int
+	mux.HandleFunc("/v2/retry", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/v2/retry", nil)
+	_, err := client.Do(req, &struct{}{}) // nolint:bodyclose
+	var er *ErrorResponse
+	if !errors.As(err, &er) || er.Response.StatusCode != http.StatusBadGateway {
+		t.Fatalf("got: %v, want: 502 error", err)
+	}
+	if calls != 3 {
+		t.Errorf("got: %v calls, want: 3", calls)
+	}
+}
+
+func TestClient_Do_noRetryForPost(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+
+	var calls int
+	mux.HandleFunc("/v2/retry", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req, _ := client.NewRequest(context.Background(), http.MethodPost, "/v2/retry", strings.NewReader("a=b"))
+	_, err := client.Do(req, &struct{}{}) // nolint:bodyclose
+	if err == nil {
+		t.Fatal("must not return nil")
+	}
+	if calls != 1 {
+		t.Errorf("got: %v calls, want: 1", calls)
+	}
+}
+
+func TestClient_Do_retryPostReplaysBody(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+	client.RetryPolicy.RetryNonIdempotent = true
+
+	var calls int
+	mux.HandleFunc("/v2/retry", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "a=b" {
+			t.Errorf("got body: %q, want: %q", body, "a=b")
+		}
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprintln(w, `{"status":"OK"}`)
+	})
+
+	req, _ := client.NewRequest(context.Background(), http.MethodPost, "/v2/retry", strings.NewReader("a=b"))
+	_, err := client.Do(req, &struct{}{}) // nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("got: %v calls, want: 2", calls)
+	}
+}
+
+func TestClient_Do_retryAfterTooLong(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = fastRetryPolicy()
+	client.RetryPolicy.MaxRetryAfter = time.Second
+
+	var calls int
+	var retryAfter string
+	mux.HandleFunc("/v2/retry", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", retryAfter)
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	for _, tt := range []struct {
+		header string
+		policy *RetryPolicy
+	}{
+		{"120", client.RetryPolicy},
+		// the default policy does not wait for a day
+		{"86400", fastRetryPolicy()},
+	} {
+		calls = 0
+		retryAfter = tt.header
+		client.RetryPolicy = tt.policy
+		req, _ := client.NewRequest(context.Background(), http.MethodGet, "/v2/retry", nil)
+		_, err := client.Do(req, &struct{}{}) // nolint:bodyclose
+		if err == nil {
+			t.Fatalf("%s: must not return nil", tt.header)
+		}
+		if calls != 1 {
+			t.Errorf("%s: got: %v calls, want: 1", tt.header, calls)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	tests := []struct {
+		header string
+		ok     bool
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"", false, 0, 0},
+		{"7", true, 7 * time.Second, 7 * time.Second},
+		{"-1", false, 0, 0},
+		{"soon", false, 0, 0},
+		{date, true, 59 * time.Minute, time.Hour},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", tt.header)
+		d, ok := retryAfter(resp)
+		if ok != tt.ok || d < tt.min || d > tt.max {
+			t.Errorf("%q: got: %v %v, want: %v in [%v, %v]", tt.header, d, ok, tt.ok, tt.min, tt.max)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.Backoff(i + 1); got != w {
+			t.Errorf("attempt %d: got: %v, want: %v", i+1, got, w)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/systemmonkey42/go-putio"
+
+// Attribute keys recorded on spans and metrics.
+const (
+	attrFileID       = attribute.Key("putio.file_id")
+	attrFileIDs      = attribute.Key("putio.file_ids")
+	attrParentID     = attribute.Key("putio.parent_id")
+	attrTransferID   = attribute.Key("putio.transfer_id")
+	attrTransferIDs  = attribute.Key("putio.transfer_ids")
+	attrZipID        = attribute.Key("putio.zip_id")
+	attrUploadLength = attribute.Key("putio.upload.length")
+	attrUploadOffset = attribute.Key("putio.upload.offset")
+	attrEndpoint     = attribute.Key("putio.endpoint")
+	attrErrorType    = attribute.Key("putio.error_type")
+	attrMethod       = attribute.Key("http.request.method")
+	attrStatusCode   = attribute.Key("http.response.status_code")
+	attrReqBytes     = attribute.Key("http.request.body.size")
+	attrRespBytes    = attribute.Key("http.response.body.size")
+)
+
+// Telemetry instruments a Client with OpenTelemetry. When set on
+// Client.Telemetry, every service method runs in its own span, each HTTP
+// attempt is recorded as a child span, and request count, error count and
+// latency metrics are collected.
+type Telemetry struct {
+	tracer   trace.Tracer
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// NewTelemetry creates the instruments used by Client. If tp or mp is nil,
+// the global provider registered with the otel package is used.
+func NewTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) (*Telemetry, error) {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+
+	t := &Telemetry{tracer: tp.Tracer(instrumentationName)}
+	var err error
+	t.requests, err = meter.Int64Counter(
+		"putio.client.requests",
+		metric.WithDescription("Number of HTTP requests sent to put.io."),
+	)
+	if err != nil {
+		return nil, err // nolint:wrapcheck
+	}
+	t.errors, err = meter.Int64Counter(
+		"putio.client.errors",
+		metric.WithDescription("Number of HTTP requests that failed or returned an error status."),
+	)
+	if err != nil {
+		return nil, err // nolint:wrapcheck
+	}
+	t.duration, err = meter.Float64Histogram(
+		"putio.client.request.duration",
+		metric.WithDescription("Duration of HTTP requests sent to put.io."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err // nolint:wrapcheck
+	}
+	return t, nil
+}
+
+// spanKey is the context key of the span started by a service method.
+type spanKey struct{}
+
+// noopSpan is returned by startSpan when telemetry is disabled.
+var noopSpan = trace.SpanFromContext(context.Background())
+
+// startSpan starts the span of a service method. The span must be ended by
+// the caller.
+func (c *Client) startSpan(
+	ctx context.Context,
+	name string,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	if c.Telemetry == nil {
+		return ctx, noopSpan
+	}
+	ctx, span := c.Telemetry.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	recordError(span, err)
+	span.End()
+}
+
+// recordError records err on the service method span of ctx.
+func (c *Client) recordError(ctx context.Context, err error) {
+	if span, ok := ctx.Value(spanKey{}).(trace.Span); ok {
+		recordError(span, err)
+	}
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	var er *ErrorResponse
+	if errors.As(err, &er) && er.Type != "" {
+		span.SetAttributes(attrErrorType.String(er.Type))
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// middleware records a span and metrics for each HTTP attempt.
+func (t *Telemetry) middleware(next Doer) Doer {
+	return DoerFunc(func(r *http.Request) (*http.Response, error) {
+		endpoint := endpointOf(r)
+		attrs := []attribute.KeyValue{attrMethod.String(r.Method), attrEndpoint.String(endpoint)}
+
+		ctx, span := t.tracer.Start(
+			r.Context(),
+			"HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+		parent, _ := r.Context().Value(spanKey{}).(trace.Span)
+		if parent != nil {
+			parent.SetAttributes(attrEndpoint.String(endpoint))
+		}
+
+		var body *countingReader
+		if r.Body != nil && r.Body != http.NoBody {
+			body = &countingReader{r: r.Body}
+			r = r.Clone(ctx)
+			r.Body = body
+		} else {
+			r = r.WithContext(ctx)
+		}
+
+		start := time.Now()
+		resp, err := next.Do(r)
+		elapsed := time.Since(start).Seconds()
+
+		if body != nil {
+			span.SetAttributes(attrReqBytes.Int64(body.n))
+		}
+		failed := err != nil
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			attrs = append(attrs, attrStatusCode.Int(resp.StatusCode))
+			span.SetAttributes(attrStatusCode.Int(resp.StatusCode))
+			if resp.ContentLength >= 0 {
+				span.SetAttributes(attrRespBytes.Int64(resp.ContentLength))
+			}
+			if parent != nil {
+				parent.SetAttributes(attrStatusCode.Int(resp.StatusCode))
+			}
+			if resp.StatusCode >= 400 {
+				failed = true
+				span.SetStatus(codes.Error, resp.Status)
+			}
+		}
+
+		set := metric.WithAttributes(attrs...)
+		t.requests.Add(ctx, 1, set)
+		t.duration.Record(ctx, elapsed, set)
+		if failed {
+			t.errors.Add(ctx, 1, set)
+		}
+		return resp, err
+	})
+}
+
+// endpointOf returns the path of r with IDs replaced by a placeholder, so
+// that it can be used as a low cardinality attribute.
+func endpointOf(r *http.Request) string {
+	segments := strings.Split(r.URL.Path, "/")
+	for i, s := range segments {
+		if isID(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isID reports whether a path segment looks like a numeric ID or an opaque
+// token, such as the ID part of a tus upload location.
+func isID(s string) bool {
+	if s == "" {
+		return false
+	}
+	if len(s) >= 20 {
+		return true
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.ReadCloser
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err // nolint:wrapcheck
+}
+
+func (c *countingReader) Close() error {
+	return c.r.Close() // nolint:wrapcheck
+}
@@ -0,0 +1,132 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupTelemetry(t *testing.T) (*tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	tel, err := NewTelemetry(
+		sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Telemetry = tel
+	return exporter, reader
+}
+
+func spanAttr(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTelemetry_spans(t *testing.T) {
+	setup()
+	defer teardown()
+	exporter, _ := setupTelemetry(t)
+
+	mux.HandleFunc("/v2/files/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"file":{"id":1,"name":"a"},"status":"OK"}`)
+	})
+
+	_, err := client.Files.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got: %v spans, want: 2", len(spans))
+	}
+	httpSpan, methodSpan := spans[0], spans[1]
+	if methodSpan.Name != "FilesService.Get" {
+		t.Errorf("got: %q, want: %q", methodSpan.Name, "FilesService.Get")
+	}
+	if httpSpan.Parent.SpanID() != methodSpan.SpanContext.SpanID() {
+		t.Errorf("http span is not a child of the method span")
+	}
+	if v, _ := spanAttr(methodSpan, attrFileID); v.AsInt64() != 1 {
+		t.Errorf("got file id: %v, want: 1", v.AsInt64())
+	}
+	if v, _ := spanAttr(httpSpan, attrEndpoint); v.AsString() != "/v2/files/{id}" {
+		t.Errorf("got endpoint: %q, want: %q", v.AsString(), "/v2/files/{id}")
+	}
+	if v, _ := spanAttr(httpSpan, attrStatusCode); v.AsInt64() != http.StatusOK {
+		t.Errorf("got status: %v, want: %v", v.AsInt64(), http.StatusOK)
+	}
+}
+
+func TestTelemetry_errorType(t *testing.T) {
+	setup()
+	defer teardown()
+	client.RetryPolicy = nil
+	exporter, reader := setupTelemetry(t)
+
+	mux.HandleFunc("/v2/transfers/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error_type":"NotFound","error_message":"no such transfer"}`)
+	})
+
+	_, err := client.Transfers.Get(context.Background(), 1)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got: %v, want: %v", err, ErrNotFound)
+	}
+
+	spans := exporter.GetSpans()
+	methodSpan := spans[len(spans)-1]
+	if methodSpan.Status.Code != codes.Error {
+		t.Errorf("got status: %v, want: %v", methodSpan.Status.Code, codes.Error)
+	}
+	if v, _ := spanAttr(methodSpan, attrErrorType); v.AsString() != "NotFound" {
+		t.Errorf("got error type: %q, want: %q", v.AsString(), "NotFound")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = true
+		}
+	}
+	for _, name := range []string{"putio.client.requests", "putio.client.errors", "putio.client.request.duration"} {
+		if !got[name] {
+			t.Errorf("metric %q is not recorded", name)
+		}
+	}
+}
+
+func TestTelemetry_disabled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, span := client.startSpan(context.Background(), "FilesService.Get")
+	defer span.End()
+	if span.IsRecording() {
+		t.Error("span must not be recording when telemetry is disabled")
+	}
+	if ctx.Value(spanKey{}) != nil {
+		t.Error("span must not be stored in context when telemetry is disabled")
+	}
+}
@@ -16,6 +16,9 @@ type TransfersService struct {
 // List lists all active transfers. If a transfer is completed, it will not be
 // available in response.
 func (t *TransfersService) List(ctx context.Context) ([]Transfer, error) {
+	ctx, span := t.client.startSpan(ctx, "TransfersService.List")
+	defer span.End()
+
 	req, err := t.client.NewRequest(ctx, http.MethodGet, "/v2/transfers/list", nil)
 	if err != nil {
 		return nil, err
@@ -37,6 +40,9 @@ func (t *TransfersService) List(ctx context.Context) ([]Transfer, error) {
 // value is given, user's preferred download folder is used. CallbackURL is
 // used to send a POST request after the transfer is finished downloading.
 func (t *TransfersService) Add(ctx context.Context, urlStr string, parent int64, callbackURL string) (Transfer, error) {
+	ctx, span := t.client.startSpan(ctx, "TransfersService.Add", attrParentID.Int64(parent))
+	defer span.End()
+
 	if urlStr == "" {
 		return Transfer{}, ErrEmptyURL
 	}
@@ -71,6 +77,9 @@ func (t *TransfersService) Add(ctx context.Context, urlStr string, parent int64,
 
 // Get returns the given transfer's properties.
 func (t *TransfersService) Get(ctx context.Context, id int64) (Transfer, error) {
+	ctx, span := t.client.startSpan(ctx, "TransfersService.Get", attrTransferID.Int64(id))
+	defer span.End()
+
 	req, err := t.client.NewRequest(ctx, http.MethodGet, "/v2/transfers/"+itoa(id), nil)
 	if err != nil {
 		return Transfer{}, err
@@ -89,6 +98,9 @@ func (t *TransfersService) Get(ctx context.Context, id int64) (Transfer, error)
 
 // Retry retries previously failed transfer.
 func (t *TransfersService) Retry(ctx context.Context, id int64) (Transfer, error) {
+	ctx, span := t.client.startSpan(ctx, "TransfersService.Retry", attrTransferID.Int64(id))
+	defer span.End()
+
 	params := url.Values{}
 	params.Set("id", itoa(id))
 
@@ -111,6 +123,9 @@ func (t *TransfersService) Retry(ctx context.Context, id int64) (Transfer, error
 
 // Cancel deletes given transfers.
 func (t *TransfersService) Cancel(ctx context.Context, ids ...int64) error {
+	ctx, span := t.client.startSpan(ctx, "TransfersService.Cancel", attrTransferIDs.Int64Slice(ids))
+	defer span.End()
+
 	if len(ids) == 0 {
 		return ErrNoFileIDIsGiven
 	}
@@ -139,6 +154,9 @@ func (t *TransfersService) Cancel(ctx context.Context, ids ...int64) error {
 
 // Clean removes completed transfers from the transfer list.
 func (t *TransfersService) Clean(ctx context.Context) error {
+	ctx, span := t.client.startSpan(ctx, "TransfersService.Clean")
+	defer span.End()
+
 	req, err := t.client.NewRequest(ctx, http.MethodPost, "/v2/transfers/clean", nil)
 	if err != nil {
 		return err
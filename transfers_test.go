@@ -2,6 +2,7 @@ package putio
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -404,3 +405,53 @@ func TestTransfers_Clean(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestTransferStatus(t *testing.T) {
+	tests := []struct {
+		status                                  TransferStatus
+		active, completed, failed, terminal, ok bool
+	}{
+		{TransferStatusInQueue, true, false, false, false, true},
+		{TransferStatusPreparingDownload, true, false, false, false, true},
+		{TransferStatusDownloading, true, false, false, false, true},
+		{TransferStatusSeeding, false, true, false, true, true},
+		{TransferStatusCompleted, false, true, false, true, true},
+		{TransferStatusError, false, false, true, true, true},
+		{"STOPPING", false, false, false, false, false},
+	}
+	for _, tt := range tests {
+		s := tt.status
+		got := []bool{s.IsActive(), s.IsCompleted(), s.IsFailed(), s.IsTerminal(), s.IsKnown()}
+		want := []bool{tt.active, tt.completed, tt.failed, tt.terminal, tt.ok}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%v: got: %v, want: %v", s, got, want)
+		}
+	}
+}
+
+func TestTransfer_JSON_unknownValues(t *testing.T) {
+	var tr Transfer
+	err := json.Unmarshal([]byte(`{"status":"STOPPING","type":"LIVE_STREAM"}`), &tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Status != "STOPPING" || tr.Status.IsKnown() || tr.Type != "LIVE_STREAM" || tr.Type.IsKnown() {
+		t.Errorf("got: %q, %q", tr.Status, tr.Type)
+	}
+
+	b, err := json.Marshal(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"status":"STOPPING"`) || !strings.Contains(string(b), `"type":"LIVE_STREAM"`) {
+		t.Errorf("got: %s", b)
+	}
+
+	err = json.Unmarshal([]byte(`{"status":"SEEDING","type":"TORRENT"}`), &tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Status != TransferStatusSeeding || tr.Type != TransferTypeTorrent || !tr.Type.IsKnown() {
+		t.Errorf("got: %q, %q", tr.Status, tr.Type)
+	}
+}
@@ -0,0 +1,172 @@
+package putio
+
+import (
+	"context"
+	"crypto/md5"  // nolint:gosec
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// statusChecksumMismatch is the status code of a tus server rejecting a
+// chunk whose Upload-Checksum does not match.
+const statusChecksumMismatch = 460
+
+// tusChecksumAlgorithms are the Upload-Checksum algorithms the client can
+// compute, in order of preference.
+var tusChecksumAlgorithms = []struct {
+	name string
+	hash func() hash.Hash
+}{
+	{"sha256", sha256.New},
+	{"sha1", sha1.New},
+	{"md5", md5.New},
+}
+
+// TusCapabilities are the features of a tus server.
+type TusCapabilities struct {
+	// Versions are the supported versions of the protocol.
+	Versions []string
+	// Extensions are the supported extensions, such as "creation" or
+	// "checksum".
+	Extensions []string
+	// ChecksumAlgorithms are the algorithms of the checksum extension.
+	ChecksumAlgorithms []string
+	// MaxSize is the largest upload allowed, or 0 if there is no limit.
+	MaxSize int64
+}
+
+// Supports reports whether the server supports the named extension.
+func (c TusCapabilities) Supports(extension string) bool {
+	for _, ext := range c.Extensions {
+		if ext == extension {
+			return true
+		}
+	}
+	return false
+}
+
+// Capabilities asks the tus server for the features it supports.
+func (u *UploadService) Capabilities(ctx context.Context) (caps TusCapabilities, err error) {
+	ctx, span := u.client.startSpan(ctx, "UploadService.Capabilities")
+	defer func() { endSpan(span, err) }()
+
+	caps, _, err = u.options(ctx)
+	return
+}
+
+// options sends the OPTIONS request of Capabilities. It also returns the
+// status code of the response, or 0 if there is none.
+func (u *UploadService) options(ctx context.Context) (TusCapabilities, int, error) {
+	req, err := u.client.NewRequest(ctx, http.MethodOptions, u.client.TusURL.String(), nil)
+	if err != nil {
+		return TusCapabilities{}, 0, err
+	}
+	resp, err := u.client.roundTrip(req)
+	if err != nil {
+		return TusCapabilities{}, 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	u.log(ctx, "upload response", "status", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return TusCapabilities{}, resp.StatusCode, uploadError(resp)
+	}
+	caps := TusCapabilities{
+		Versions:           splitHeader(resp.Header.Get("Tus-Version")),
+		Extensions:         splitHeader(resp.Header.Get("Tus-Extension")),
+		ChecksumAlgorithms: splitHeader(resp.Header.Get("Tus-Checksum-Algorithm")),
+	}
+	if v := resp.Header.Get("Tus-Max-Size"); v != "" {
+		caps.MaxSize, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return TusCapabilities{}, resp.StatusCode, fmt.Errorf("cannot parse tus-max-size header: %w", err)
+		}
+	}
+	return caps, resp.StatusCode, nil
+}
+
+// capabilities returns the capabilities of the server, asking it until it
+// answers. A server that does not implement the OPTIONS request is taken to
+// support nothing; other failures, such as 429 and 5xx responses, may be
+// temporary and are asked again the next time.
+func (u *UploadService) capabilities(ctx context.Context) TusCapabilities {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	tusURL := u.client.TusURL.String()
+	if u.caps != nil && u.capsURL == tusURL {
+		return *u.caps
+	}
+
+	caps, status, err := u.options(ctx)
+	switch {
+	case err == nil:
+	case status == http.StatusOK || status == http.StatusNoContent:
+		// an answer that cannot be parsed will not get better
+		u.log(ctx, "cannot parse upload capabilities", "error", err)
+	case status == http.StatusNotFound || status == http.StatusMethodNotAllowed:
+	default:
+		u.log(ctx, "cannot get upload capabilities", "error", err)
+		return TusCapabilities{}
+	}
+	u.caps = &caps
+	u.capsURL = tusURL
+	return caps
+}
+
+// chunkChecksum returns the Upload-Checksum header of the next n bytes of r,
+// or of the rest of r if n is negative, and seeks r back. It returns an empty
+// string if the server does not support the checksum extension or if r
+// cannot seek, such as a pipe.
+func (u *UploadService) chunkChecksum(ctx context.Context, r io.ReadSeeker, n int64) (string, error) {
+	caps := u.capabilities(ctx)
+	if !caps.Supports("checksum") {
+		return "", nil
+	}
+	for _, alg := range tusChecksumAlgorithms {
+		for _, name := range caps.ChecksumAlgorithms {
+			if name != alg.name {
+				continue
+			}
+			start, err := r.Seek(0, io.SeekCurrent)
+			if err != nil {
+				// a pipe or a terminal, which can only be read once
+				return "", nil
+			}
+			h := alg.hash()
+			if n < 0 {
+				_, err = io.Copy(h, r)
+			} else {
+				_, err = io.CopyN(h, r, n)
+			}
+			if err != nil {
+				return "", fmt.Errorf("%w", err)
+			}
+			_, err = r.Seek(start, io.SeekStart)
+			if err != nil {
+				return "", fmt.Errorf("%w", err)
+			}
+			return alg.name + " " + base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+		}
+	}
+	return "", nil
+}
+
+// splitHeader splits a comma separated header value.
+func splitHeader(v string) []string {
+	var values []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
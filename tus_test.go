@@ -0,0 +1,222 @@
+package putio
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpload_Capabilities(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodOptions)
+		w.Header().Set("Tus-Version", "1.0.0,0.2.2")
+		w.Header().Set("Tus-Extension", "creation, checksum,termination")
+		w.Header().Set("Tus-Checksum-Algorithm", "md5,sha1")
+		w.Header().Set("Tus-Max-Size", "1073741824")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	caps, err := client.Upload.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TusCapabilities{
+		Versions:           []string{"1.0.0", "0.2.2"},
+		Extensions:         []string{"creation", "checksum", "termination"},
+		ChecksumAlgorithms: []string{"md5", "sha1"},
+		MaxSize:            1 << 30,
+	}
+	if !reflect.DeepEqual(caps, want) {
+		t.Errorf("got: %+v, want: %+v", caps, want)
+	}
+	if !caps.Supports("checksum") || caps.Supports("concatenation") {
+		t.Errorf("got: %v, %v", caps.Supports("checksum"), caps.Supports("concatenation"))
+	}
+}
+
+func TestUpload_SendFile_checksum(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var options int
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		options++
+		w.Header().Set("Tus-Extension", "creation,checksum")
+		w.Header().Set("Tus-Checksum-Algorithm", "crc32,sha1,md5")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sum := sha1.Sum(body)
+		testHeader(t, r, "Upload-Checksum", "sha1 "+base64.StdEncoding.EncodeToString(sum[:]))
+		w.Header().Set("putio-file-id", "123")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for i := 0; i < 2; i++ {
+		_, _, err := client.Upload.SendFile(context.Background(), strings.NewReader("world"), client.TusURL.String()+"abc", 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if options != 1 {
+		t.Errorf("got: %v OPTIONS requests, want: 1", options)
+	}
+}
+
+func TestUpload_SendFile_capabilitiesUnavailable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var options int
+	status := http.StatusServiceUnavailable
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		options++
+		if status == http.StatusNoContent {
+			w.Header().Set("Tus-Extension", "checksum")
+			w.Header().Set("Tus-Checksum-Algorithm", "sha1")
+		}
+		w.WriteHeader(status)
+	})
+	var checksums []string
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		checksums = append(checksums, r.Header.Get("Upload-Checksum"))
+		w.Header().Set("putio-file-id", "123")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// a failure that may be temporary is asked again, a server without
+	// OPTIONS is not
+	for _, status = range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent, http.StatusNoContent} {
+		_, _, err := client.Upload.SendFile(context.Background(), strings.NewReader("world"), client.TusURL.String()+"abc", 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if options != 3 {
+		t.Errorf("got: %v OPTIONS requests, want: 3", options)
+	}
+	if checksums[1] != "" || checksums[2] == "" || checksums[3] == "" {
+		t.Errorf("got: %q", checksums)
+	}
+
+	client.Upload.caps = nil
+	options = 0
+	for _, status = range []int{http.StatusMethodNotAllowed, http.StatusNoContent} {
+		_, _, err := client.Upload.SendFile(context.Background(), strings.NewReader("world"), client.TusURL.String()+"abc", 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if options != 1 {
+		t.Errorf("got: %v OPTIONS requests, want: 1", options)
+	}
+}
+
+func TestUpload_SendFile_checksumRejected(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Extension", "checksum")
+		w.Header().Set("Tus-Checksum-Algorithm", "sha1")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusChecksumMismatch)
+	})
+
+	_, _, err := client.Upload.SendFile(context.Background(), strings.NewReader("world"), client.TusURL.String()+"abc", 6)
+	var ce *ChecksumError
+	if !errors.As(err, &ce) || !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("got: %v, want a *ChecksumError", err)
+	}
+	if ce.Algorithm != "sha1" || ce.Offset != 6 || ce.Local == "" || ce.Remote != "" {
+		t.Errorf("got: %+v", ce)
+	}
+}
+
+func TestUpload_SendFile_crc32Mismatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// the server does not answer OPTIONS requests, so no checksum is sent
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		if v := r.Header.Get("Upload-Checksum"); v != "" {
+			t.Errorf("got checksum: %v", v)
+		}
+		_, _ = io.ReadAll(r.Body)
+		w.Header().Set("putio-file-id", "123")
+		w.Header().Set("putio-file-crc32", "3a771143")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// 3a771143 is the checksum of "world"
+	_, _, err := client.Upload.SendFile(context.Background(), strings.NewReader("world"), client.TusURL.String()+"abc", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileID, _, err := client.Upload.SendFile(context.Background(), strings.NewReader("w0rld"), client.TusURL.String()+"abc", 0)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("got: %v, want: %v", err, ErrChecksumMismatch)
+	}
+	if fileID != 123 {
+		t.Errorf("got: %v, want: 123", fileID)
+	}
+}
+
+func TestUpload_SendFile_pipe(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Extension", "checksum")
+		w.Header().Set("Tus-Checksum-Algorithm", "sha1")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		// a pipe cannot be read twice, so it is sent without a checksum
+		if v := r.Header.Get("Upload-Checksum"); v != "" {
+			t.Errorf("got checksum: %v", v)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "world" {
+			t.Errorf("got: %q, want: world", body)
+		}
+		w.Header().Set("putio-file-id", "123")
+		w.Header().Set("putio-file-crc32", "3a771143")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	go func() {
+		_, _ = pw.WriteString("world")
+		pw.Close()
+	}()
+
+	fileID, _, err := client.Upload.SendFile(context.Background(), pr, client.TusURL.String()+"abc", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileID != 123 {
+		t.Errorf("got: %v, want: 123", fileID)
+	}
+}
@@ -25,7 +25,13 @@ type PutTime struct {
 
 func (p *PutTime) UnmarshalJSON(b []byte) (err error) {
 	s := strings.Trim(string(b), "\"")
-	p.Time, err = time.Parse("2006-01-02T15:04:05", s)
+	// /events uses a space instead of "T" between date and time.
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+		p.Time, err = time.Parse(layout, s)
+		if err == nil {
+			return nil
+		}
+	}
 	return
 }
 
@@ -46,6 +52,37 @@ type File struct {
 	CRC32             string   `json:"crc32"`
 	IsShared          bool     `json:"is_shared"`
 	FileType          string   `json:"file_type"`
+
+	// These fields are only filled when requested with ListOptions.
+	StreamURL     string         `json:"stream_url"`
+	MP4StreamURL  string         `json:"mp4_stream_url"`
+	MP4Status     *MP4Status     `json:"mp4_status"`
+	VideoMetadata *VideoMetadata `json:"video_metadata"`
+}
+
+// MP4 conversion statuses.
+const (
+	MP4StatusNotAvailable = "NOT_AVAILABLE"
+	MP4StatusInQueue      = "IN_QUEUE"
+	MP4StatusConverting   = "CONVERTING"
+	MP4StatusCompleted    = "COMPLETED"
+	MP4StatusError        = "ERROR"
+)
+
+// MP4Status represents the state of the MP4 conversion of a video.
+type MP4Status struct {
+	Status      string  `json:"status"`
+	PercentDone FlexInt `json:"percent_done"`
+	Size        FlexInt `json:"size"`
+}
+
+// VideoMetadata represents the properties of a video file.
+type VideoMetadata struct {
+	Width       FlexInt   `json:"width"`
+	Height      FlexInt   `json:"height"`
+	Codec       string    `json:"codec"`
+	Duration    FlexFloat `json:"duration"`
+	AspectRatio FlexFloat `json:"aspect_ratio"`
 }
 
 func (f *File) String() string {
@@ -72,64 +109,134 @@ type Search struct {
 
 // Transfer represents a Put.io transfer state.
 type Transfer struct {
-	Availability   int      `json:"availability"`
-	CallbackURL    string   `json:"callback_url"`
-	CreatedAt      *PutTime `json:"created_at"`
-	CreatedTorrent bool     `json:"created_torrent"`
-	ClientIP       string   `json:"client_ip"`
-
-	// nolint:godox
-	// FIXME: API returns either string or float non-deterministically.
-	// CurrentRatio       float32 `json:"current_ratio"`
-
-	DownloadSpeed      int      `json:"down_speed"`
-	Downloaded         int64    `json:"downloaded"`
-	DownloadID         int64    `json:"download_id"`
-	ErrorMessage       string   `json:"error_message"`
-	EstimatedTime      int64    `json:"estimated_time"`
-	Extract            bool     `json:"extract"`
-	FileID             int64    `json:"file_id"`
-	FinishedAt         *PutTime `json:"finished_at"`
-	ID                 int64    `json:"id"`
-	IsPrivate          bool     `json:"is_private"`
-	MagnetURI          string   `json:"magneturi"`
-	Name               string   `json:"name"`
-	PeersConnected     int      `json:"peers_connected"`
-	PeersGettingFromUs int      `json:"peers_getting_from_us"`
-	PeersSendingToUs   int      `json:"peers_sending_to_us"`
-	PercentDone        int      `json:"percent_done"`
-	SaveParentID       int64    `json:"save_parent_id"`
-	SecondsSeeding     int      `json:"seconds_seeding"`
-	Size               int      `json:"size"`
-	Source             string   `json:"source"`
-	Status             string   `json:"status"`
-	StatusMessage      string   `json:"status_message"`
-	SubscriptionID     int      `json:"subscription_id"`
-	TorrentLink        string   `json:"torrent_link"`
-	TrackerMessage     string   `json:"tracker_message"`
-	Trackers           string   `json:"tracker"`
-	Type               string   `json:"type"`
-	UploadSpeed        int      `json:"up_speed"`
-	Uploaded           int64    `json:"uploaded"`
+	Availability       FlexInt        `json:"availability"`
+	CallbackURL        string         `json:"callback_url"`
+	CreatedAt          *PutTime       `json:"created_at"`
+	CreatedTorrent     bool           `json:"created_torrent"`
+	ClientIP           string         `json:"client_ip"`
+	CurrentRatio       FlexFloat      `json:"current_ratio"`
+	DownloadSpeed      FlexInt        `json:"down_speed"`
+	Downloaded         FlexInt        `json:"downloaded"`
+	DownloadID         int64          `json:"download_id"`
+	ErrorMessage       string         `json:"error_message"`
+	EstimatedTime      FlexInt        `json:"estimated_time"`
+	Extract            bool           `json:"extract"`
+	FileID             int64          `json:"file_id"`
+	FinishedAt         *PutTime       `json:"finished_at"`
+	ID                 int64          `json:"id"`
+	IsPrivate          bool           `json:"is_private"`
+	MagnetURI          string         `json:"magneturi"`
+	Name               string         `json:"name"`
+	PeersConnected     FlexInt        `json:"peers_connected"`
+	PeersGettingFromUs FlexInt        `json:"peers_getting_from_us"`
+	PeersSendingToUs   FlexInt        `json:"peers_sending_to_us"`
+	PercentDone        FlexInt        `json:"percent_done"`
+	SaveParentID       int64          `json:"save_parent_id"`
+	SecondsSeeding     FlexInt        `json:"seconds_seeding"`
+	Size               FlexInt        `json:"size"`
+	Source             string         `json:"source"`
+	Status             TransferStatus `json:"status"`
+	StatusMessage      string         `json:"status_message"`
+	SubscriptionID     FlexInt        `json:"subscription_id"`
+	TorrentLink        string         `json:"torrent_link"`
+	TrackerMessage     string         `json:"tracker_message"`
+	Trackers           string         `json:"tracker"`
+	Type               TransferType   `json:"type"`
+	UploadSpeed        FlexInt        `json:"up_speed"`
+	Uploaded           FlexInt        `json:"uploaded"`
+}
+
+// ETA returns the estimated time left until the transfer is downloaded, or
+// zero if it is unknown.
+func (t *Transfer) ETA() time.Duration {
+	return time.Duration(t.EstimatedTime) * time.Second
+}
+
+// TransferStatus is the status of a transfer. Values the API adds later are
+// kept as they are.
+type TransferStatus string
+
+// Transfer statuses.
+const (
+	TransferStatusInQueue           TransferStatus = "IN_QUEUE"
+	TransferStatusWaiting           TransferStatus = "WAITING"
+	TransferStatusPreparingDownload TransferStatus = "PREPARING_DOWNLOAD"
+	TransferStatusDownloading       TransferStatus = "DOWNLOADING"
+	TransferStatusCompleting        TransferStatus = "COMPLETING"
+	TransferStatusSeeding           TransferStatus = "SEEDING"
+	TransferStatusCompleted         TransferStatus = "COMPLETED"
+	TransferStatusError             TransferStatus = "ERROR"
+)
+
+// IsActive reports whether the transfer is queued or downloading.
+func (s TransferStatus) IsActive() bool {
+	switch s {
+	case TransferStatusInQueue, TransferStatusWaiting, TransferStatusPreparingDownload,
+		TransferStatusDownloading, TransferStatusCompleting:
+		return true
+	}
+	return false
+}
+
+// IsCompleted reports whether the transfer has finished downloading. A
+// seeding transfer is completed.
+func (s TransferStatus) IsCompleted() bool {
+	return s == TransferStatusSeeding || s == TransferStatusCompleted
+}
+
+// IsFailed reports whether the transfer has failed.
+func (s TransferStatus) IsFailed() bool {
+	return s == TransferStatusError
+}
+
+// IsTerminal reports whether the transfer is completed or has failed, so
+// its status changes no more unless it is retried.
+func (s TransferStatus) IsTerminal() bool {
+	return s.IsCompleted() || s.IsFailed()
+}
+
+// IsKnown reports whether s is one of the TransferStatus constants.
+func (s TransferStatus) IsKnown() bool {
+	return s.IsActive() || s.IsTerminal()
+}
+
+// TransferType is the kind of source of a transfer. Values the API adds later
+// are kept as they are.
+type TransferType string
+
+// Transfer types.
+const (
+	TransferTypeTorrent  TransferType = "TORRENT"
+	TransferTypeURL      TransferType = "URL"
+	TransferTypePlaylist TransferType = "PLAYLIST"
+)
+
+// IsKnown reports whether t is one of the TransferType constants.
+func (t TransferType) IsKnown() bool {
+	switch t {
+	case TransferTypeTorrent, TransferTypeURL, TransferTypePlaylist:
+		return true
+	}
+	return false
 }
 
 // AccountInfo represents user's account information.
 type AccountInfo struct {
-	AccountActive           bool   `json:"account_active"`
-	AvatarURL               string `json:"avatar_url"`
-	DaysUntilFilesDeletion  int    `json:"days_until_files_deletion"`
-	DefaultSubtitleLanguage string `json:"default_subtitle_language"`
+	AccountActive           bool    `json:"account_active"`
+	AvatarURL               string  `json:"avatar_url"`
+	DaysUntilFilesDeletion  FlexInt `json:"days_until_files_deletion"`
+	DefaultSubtitleLanguage string  `json:"default_subtitle_language"`
 	Disk                    struct {
-		Avail int64 `json:"avail"`
-		Size  int64 `json:"size"`
-		Used  int64 `json:"used"`
+		Avail FlexInt `json:"avail"`
+		Size  FlexInt `json:"size"`
+		Used  FlexInt `json:"used"`
 	} `json:"disk"`
 	HasVoucher                bool     `json:"has_voucher"`
 	Mail                      string   `json:"mail"`
-	MonthlyBandwidthUsage     int64    `json:"monthly_bandwidth_usage"`
+	MonthlyBandwidthUsage     FlexInt  `json:"monthly_bandwidth_usage"`
 	PlanExpirationDate        string   `json:"plan_expiration_date"`
 	Settings                  Settings `json:"settings"`
-	SimultaneousDownloadLimit int      `json:"simultaneous_download_limit"`
+	SimultaneousDownloadLimit FlexInt  `json:"simultaneous_download_limit"`
 	SubtitleLanguages         []string `json:"subtitle_languages"`
 	UserID                    int64    `json:"user_id"`
 	Username                  string   `json:"username"`
@@ -3,26 +3,44 @@ package putio
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // UploadService uses TUS (resumable upload protocol) for sending files to put.io.
 type UploadService struct {
 	// Log is a user supplied function to collect log messages from upload methods.
+	//
+	// Deprecated: Set Client.Logger to receive structured records instead.
 	Log func(message string)
 
 	client *Client
+
+	// mu guards the capabilities of the server at capsURL
+	mu      sync.Mutex
+	caps    *TusCapabilities
+	capsURL string
 }
 
-func (u *UploadService) log(message string) {
-	if u.Log != nil {
-		u.Log(message)
+// log writes a debug record to the client's logger and passes the same
+// message to the legacy Log function as "msg key=value ...".
+func (u *UploadService) log(ctx context.Context, msg string, args ...interface{}) {
+	u.client.logger().DebugContext(ctx, msg, args...)
+	if u.Log == nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
 	}
+	u.Log(b.String())
 }
 
 // CreateUpload is used for beginning new upload. Use returned location in SendFile function.
@@ -32,8 +50,16 @@ func (u *UploadService) CreateUpload(
 	parentID, length int64,
 	overwrite bool,
 ) (location string, err error) {
-	u.log(fmt.Sprintf("Creating upload %q at parent=%d", filename, parentID))
-	req, err := u.client.NewRequest(ctx, http.MethodPost, "$upload-tus$", nil)
+	ctx, span := u.client.startSpan(
+		ctx,
+		"UploadService.CreateUpload",
+		attrParentID.Int64(parentID),
+		attrUploadLength.Int64(length),
+	)
+	defer func() { endSpan(span, err) }()
+
+	u.log(ctx, "creating upload", "filename", filename, "parent_id", parentID)
+	req, err := u.client.NewRequest(ctx, http.MethodPost, u.client.TusURL.String(), nil)
 	if err != nil {
 		return
 	}
@@ -47,7 +73,7 @@ func (u *UploadService) CreateUpload(
 	req.Header.Set("Upload-Length", strconv.FormatInt(length, 10))
 	req.Header.Set("Upload-Metadata", encodeMetadata(metadata))
 
-	resp, err := u.client.client.Do(req)
+	resp, err := u.client.roundTrip(req)
 	if err != nil {
 		return
 	}
@@ -55,9 +81,9 @@ func (u *UploadService) CreateUpload(
 		_ = resp.Body.Close()
 	}()
 
-	u.log(fmt.Sprintln("Status code:", resp.StatusCode))
+	u.log(ctx, "upload response", "status", resp.StatusCode)
 	if resp.StatusCode != http.StatusCreated {
-		err = fmt.Errorf("%w status: %d", ErrUnexpected, resp.StatusCode)
+		err = uploadError(resp)
 		return
 	}
 	location = resp.Header.Get("Location")
@@ -66,69 +92,137 @@ func (u *UploadService) CreateUpload(
 
 // SendFile sends the contents of the file to put.io.
 // In case of an transmission error, you can resume upload but you have to get the correct offset from server by
-// calling GetOffset and must seek to the new offset on io.Reader.
+// calling GetOffset and must seek to the new offset on io.Reader. Uploader does all of this.
+//
+// If r is an io.ReadSeeker that can seek and the server supports the tus checksum extension, r is read twice:
+// once to compute the Upload-Checksum header, and once to send it. When offset is 0, the CRC32 of the data sent is compared with
+// the one computed by put.io, and a *ChecksumError is returned if they differ.
 func (u *UploadService) SendFile(
 	ctx context.Context,
 	r io.Reader,
 	location string,
 	offset int64,
 ) (fileID int64, crc32 string, err error) {
-	u.log(fmt.Sprintf("Sending file %q offset=%d", location, offset))
+	ctx, span := u.client.startSpan(ctx, "UploadService.SendFile", attrUploadOffset.Int64(offset))
+	defer func() { endSpan(span, err) }()
+
+	u.log(ctx, "sending file", "location", location, "offset", offset)
+	var checksum string
+	if rs, ok := r.(io.ReadSeeker); ok {
+		checksum, err = u.chunkChecksum(ctx, rs, -1)
+		if err != nil {
+			return
+		}
+	}
+	var crc runningCRC32
+	var cr *crcReader
+	if offset == 0 {
+		cr = &crcReader{r: r}
+		r = cr
+	}
+	result, err := u.send(ctx, r, location, offset, checksum)
+	if err != nil {
+		return
+	}
+	fileID, err = strconv.ParseInt(result.fileID, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("cannot parse putio-file-id header: %w", err)
+		return
+	}
+	crc32 = result.crc32
+	if cr != nil {
+		crc.add(cr)
+		err = checkCRC32(crc.sum, result.crc32)
+	}
+	return
+}
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+// sendResult is the response of the tus server to a PATCH request.
+type sendResult struct {
+	// offset is the offset after the sent data, -1 if not reported
+	offset int64
+	// fileID and crc32 are only set once the upload is complete
+	fileID string
+	crc32  string
+}
+
+// send sends the contents of r at offset. Unlike SendFile, r may hold only a
+// part of the rest of the file. A non-empty checksum is sent as the
+// Upload-Checksum header.
+func (u *UploadService) send(
+	ctx context.Context,
+	r io.Reader,
+	location string,
+	offset int64,
+	checksum string,
+) (sendResult, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
 
 	// Stop upload if speed is too slow.
 	// Wrap reader so each read call resets the timer that cancels the request on certain duration.
 	if u.client.Timeout > 0 {
-		r = &timerResetReader{r: r, timer: time.AfterFunc(u.client.Timeout, cancel), timeout: u.client.Timeout}
+		timer := time.AfterFunc(u.client.Timeout, func() { cancel(ErrUploadStalled) })
+		defer timer.Stop()
+		r = &timerResetReader{r: r, timer: timer, timeout: u.client.Timeout}
 	}
 
 	req, err := u.client.NewRequest(ctx, http.MethodPatch, location, r)
 	if err != nil {
-		return
-	}
-
-	// putio.Client.NewRequests add another context for handling Client.Timeout. Replace it with original context.
-	// Request must not be cancelled on timeout because sending upload body takes a long time.
-	// We will be using timerResetReader for tracking uploaded bytes and doint cancellation there.
-	if u.client.Timeout > 0 {
-		req = req.WithContext(ctx)
+		return sendResult{}, err
 	}
 
 	req.Header.Set("content-type", "application/offset+octet-stream")
 	req.Header.Set("upload-offset", strconv.FormatInt(offset, 10))
-	resp, err := u.client.client.Do(req)
+	if checksum != "" {
+		req.Header.Set("upload-checksum", checksum)
+	}
+	resp, err := u.client.roundTrip(req)
 	if err != nil {
-		return
+		if errors.Is(context.Cause(ctx), ErrUploadStalled) {
+			// not context.Canceled, which would make the stall look final
+			return sendResult{}, fmt.Errorf("%w: %v", ErrUploadStalled, err) // nolint:errorlint
+		}
+		return sendResult{}, fmt.Errorf("%w", err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
-	u.log(fmt.Sprintln("Status code:", resp.StatusCode))
+	u.log(ctx, "upload response", "status", resp.StatusCode)
+	if resp.StatusCode == statusChecksumMismatch {
+		alg, sum, _ := strings.Cut(checksum, " ")
+		return sendResult{}, &ChecksumError{Algorithm: alg, Offset: offset, Local: sum}
+	}
 	if resp.StatusCode != http.StatusNoContent {
-		err = fmt.Errorf("%w status: %d", ErrUnexpected, resp.StatusCode)
-		return
+		return sendResult{}, uploadError(resp)
 	}
-	fileID, err = strconv.ParseInt(resp.Header.Get("putio-file-id"), 10, 64)
-	if err != nil {
-		err = fmt.Errorf("cannot parse putio-file-id header: %w", err)
-		return
+	result := sendResult{
+		offset: -1,
+		fileID: resp.Header.Get("putio-file-id"),
+		crc32:  resp.Header.Get("putio-file-crc32"),
 	}
-	crc32 = resp.Header.Get("putio-file-crc32")
-	return
+	if v := resp.Header.Get("upload-offset"); v != "" {
+		result.offset, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return sendResult{}, fmt.Errorf("cannot parse upload-offset header: %w", err)
+		}
+	}
+	return result, nil
 }
 
 // GetOffset returns the offset at the server.
 func (u *UploadService) GetOffset(ctx context.Context, location string) (n int64, err error) {
-	u.log(fmt.Sprintf("Getting upload offset %q", location))
+	ctx, span := u.client.startSpan(ctx, "UploadService.GetOffset")
+	defer func() { endSpan(span, err) }()
+
+	u.log(ctx, "getting upload offset", "location", location)
 	req, err := u.client.NewRequest(ctx, http.MethodHead, location, nil)
 	if err != nil {
 		return
 	}
 
-	resp, err := u.client.client.Do(req)
+	resp, err := u.client.roundTrip(req)
 	if err != nil {
 		return
 	}
@@ -136,25 +230,31 @@ func (u *UploadService) GetOffset(ctx context.Context, location string) (n int64
 		_ = resp.Body.Close()
 	}()
 
-	u.log(fmt.Sprintln("Status code:", resp.StatusCode))
+	u.log(ctx, "upload response", "status", resp.StatusCode)
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("%w status: %d", ErrUnexpected, resp.StatusCode)
+		err = uploadError(resp)
 		return
 	}
 	n, err = strconv.ParseInt(resp.Header.Get("upload-offset"), 10, 64)
-	u.log(fmt.Sprintln("uploadJob offset:", n))
-	return n, fmt.Errorf("%w", err)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse upload-offset header: %w", err)
+	}
+	u.log(ctx, "upload offset", "offset", n)
+	return n, nil
 }
 
 // TerminateUpload removes incomplete file from the server.
 func (u *UploadService) TerminateUpload(ctx context.Context, location string) (err error) {
-	u.log(fmt.Sprintf("Terminating upload %q", location))
+	ctx, span := u.client.startSpan(ctx, "UploadService.TerminateUpload")
+	defer func() { endSpan(span, err) }()
+
+	u.log(ctx, "terminating upload", "location", location)
 	req, err := u.client.NewRequest(ctx, http.MethodDelete, location, nil)
 	if err != nil {
 		return
 	}
 
-	resp, err := u.client.client.Do(req)
+	resp, err := u.client.roundTrip(req)
 	if err != nil {
 		return
 	}
@@ -162,14 +262,24 @@ func (u *UploadService) TerminateUpload(ctx context.Context, location string) (e
 		_ = resp.Body.Close()
 	}()
 
-	u.log(fmt.Sprintln("Status code:", resp.StatusCode))
+	u.log(ctx, "upload response", "status", resp.StatusCode)
 	if resp.StatusCode != http.StatusNoContent {
-		err = fmt.Errorf("%w status: %d", ErrUnexpected, resp.StatusCode)
+		err = uploadError(resp)
 		return
 	}
 	return nil
 }
 
+// uploadError returns the error of an unexpected response of the tus server.
+// It matches ErrUnexpected and, for HTTP errors, an *ErrorResponse.
+func uploadError(resp *http.Response) error {
+	err := fmt.Errorf("%w status: %d", ErrUnexpected, resp.StatusCode)
+	if er := checkResponse(resp); er != nil {
+		return fmt.Errorf("%w: %w", err, er)
+	}
+	return err
+}
+
 func encodeMetadata(metadata map[string]string) string {
 	encoded := make([]string, 0, len(metadata))
 	for k, v := range metadata {
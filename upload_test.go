@@ -0,0 +1,165 @@
+package putio
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUpload_CreateUpload(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		testHeader(t, r, "Upload-Length", "11")
+		if r.Host != client.TusURL.Host {
+			t.Errorf("got host: %v, want: %v", r.Host, client.TusURL.Host)
+		}
+		metadata := map[string]string{}
+		for _, kv := range strings.Split(r.Header.Get("Upload-Metadata"), ",") {
+			parts := strings.SplitN(kv, " ", 2)
+			v, _ := base64.StdEncoding.DecodeString(parts[1])
+			metadata[parts[0]] = string(v)
+		}
+		if metadata["name"] != "hello.txt" || metadata["parent_id"] != "42" || metadata["overwrite"] != "true" {
+			t.Errorf("got metadata: %v", metadata)
+		}
+		w.Header().Set("Location", client.TusURL.String()+"abc")
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	location, err := client.Upload.CreateUpload(context.Background(), "hello.txt", 42, 11, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := client.TusURL.String() + "abc"; location != want {
+		t.Errorf("got: %v, want: %v", location, want)
+	}
+}
+
+func TestUpload_SendFile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+		testHeader(t, r, "Content-Type", "application/offset+octet-stream")
+		testHeader(t, r, "Upload-Offset", "6")
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "world" {
+			t.Errorf("got body: %q, want: %q", body, "world")
+		}
+		w.Header().Set("putio-file-id", "123")
+		w.Header().Set("putio-file-crc32", "0d4a1185")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	fileID, crc32, err := client.Upload.SendFile(
+		context.Background(),
+		strings.NewReader("world"),
+		client.TusURL.String()+"abc",
+		6,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileID != 123 {
+		t.Errorf("got: %v, want: 123", fileID)
+	}
+	if crc32 != "0d4a1185" {
+		t.Errorf("got: %v, want: 0d4a1185", crc32)
+	}
+}
+
+func TestUpload_GetOffset(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodHead)
+		w.Header().Set("Upload-Offset", "6")
+	})
+
+	offset, err := client.Upload.GetOffset(context.Background(), client.TusURL.String()+"abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 6 {
+		t.Errorf("got: %v, want: 6", offset)
+	}
+}
+
+func TestUpload_TerminateUpload(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/files/abc", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/files/missing", http.NotFound)
+
+	err := client.Upload.TerminateUpload(context.Background(), client.TusURL.String()+"abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.Upload.TerminateUpload(context.Background(), client.TusURL.String()+"missing")
+	if err == nil {
+		t.Fatal("must not return nil")
+	}
+}
+
+func TestFiles_Upload(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/files/upload", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		if r.Host != client.UploadURL.Host {
+			t.Errorf("got host: %v, want: %v", r.Host, client.UploadURL.Host)
+		}
+		if got := r.FormValue("parent_id"); got != "42" {
+			t.Errorf("got parent_id: %v, want: 42", got)
+		}
+		f, h, err := r.FormFile("file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(f)
+		if h.Filename != "hello.txt" || string(body) != "hello" {
+			t.Errorf("got file: %q %q", h.Filename, body)
+		}
+		fmt.Fprintln(w, `{"file":{"id":1,"name":"hello.txt","size":5},"status":"OK"}`)
+	})
+
+	upload, err := client.Files.Upload(context.Background(), strings.NewReader("hello"), "hello.txt", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upload.File == nil || upload.File.ID != 1 {
+		t.Errorf("got: %v, want file 1", upload.File)
+	}
+	if upload.Transfer != nil {
+		t.Errorf("got: %v, want nil transfer", upload.Transfer)
+	}
+}
+
+func TestNewRequest_uploadEndpoints(t *testing.T) {
+	setup()
+	defer teardown()
+
+	req, _ := client.NewRequest(context.Background(), http.MethodPost, "$upload$", nil)
+	if req.URL.String() != client.UploadURL.String() {
+		t.Errorf("got: %v, want: %v", req.URL, client.UploadURL)
+	}
+	req, _ = client.NewRequest(context.Background(), http.MethodPost, "$upload-tus$", nil)
+	if req.URL.String() != client.TusURL.String() {
+		t.Errorf("got: %v, want: %v", req.URL, client.TusURL)
+	}
+}
@@ -0,0 +1,303 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultUploadDirWorkers is used when UploadDirOptions.Workers is zero.
+const defaultUploadDirWorkers = 4
+
+// UploadDirOptions configure Uploader.UploadDir.
+type UploadDirOptions struct {
+	// Workers is the number of files uploaded at the same time. The default
+	// is 4.
+	Workers int
+
+	// Include, if not empty, limits the upload to the files matching one of
+	// its patterns. Folders are always recreated.
+	Include []string
+
+	// Exclude skips the files and folders matching one of its patterns,
+	// including the contents of excluded folders.
+	//
+	// Patterns have the syntax of path.Match. A pattern containing a slash
+	// is matched against the slash separated path relative to the uploaded
+	// directory; any other pattern is matched against the base name.
+	Exclude []string
+}
+
+// UploadDirResult is the outcome of uploading a single file or creating a
+// single folder.
+type UploadDirResult struct {
+	// Path is the slash separated path relative to the uploaded directory.
+	Path string
+	// Dir reports whether Path is a folder.
+	Dir bool
+	// Size is the size of the local file.
+	Size int64
+	// FileID is the ID of the file or folder on put.io, if it exists.
+	FileID int64
+	// Skipped reports that the file was not uploaded because an identical
+	// one already exists.
+	Skipped bool
+	// Err is the error of the upload or of the folder creation.
+	Err error
+}
+
+// UploadDirReport lists the result of every file and folder of an upload,
+// sorted by path.
+type UploadDirReport struct {
+	Results []UploadDirResult
+}
+
+// Failed returns the results with an error.
+func (r *UploadDirReport) Failed() []UploadDirResult {
+	var failed []UploadDirResult
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Err returns the errors of all failed results joined, or nil.
+func (r *UploadDirReport) Err() error {
+	var errs []error
+	for _, res := range r.Failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", res.Path, res.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// UploadDir uploads the directory tree at localRoot into the folder with the
+// given ID. The folder itself is not created; use CreateFolder or MkdirAll
+// for that. Subdirectories are created, or reused if a folder of the same
+// name exists, and files are uploaded with UploadFile by a pool of workers.
+//
+// A file is skipped if a file of the same name, size and CRC32 already
+// exists on put.io. A file that exists with different contents is uploaded
+// next to it, or replaces it if Overwrite is set. Symbolic links and other
+// irregular files are ignored. Progress, if set, is called for each file
+// separately and may be called concurrently.
+//
+// The failure of a file or folder does not stop the upload; it is recorded
+// in the report. The returned error is that of reading localRoot, of listing
+// the folder with the given ID or of the context. Nil opts is equivalent to
+// the zero UploadDirOptions.
+func (u *Uploader) UploadDir(
+	ctx context.Context,
+	localRoot string,
+	parentID int64,
+	opts *UploadDirOptions,
+) (report *UploadDirReport, err error) {
+	ctx, span := u.client.startSpan(ctx, "Uploader.UploadDir", attrParentID.Int64(parentID))
+	defer func() { endSpan(span, err) }()
+
+	d := &dirUploader{u: u, jobs: make(chan uploadJob)}
+	if opts != nil {
+		d.opts = *opts
+	}
+	if d.opts.Workers <= 0 {
+		d.opts.Workers = defaultUploadDirWorkers
+	}
+	for _, patterns := range [][]string{d.opts.Include, d.opts.Exclude} {
+		for _, pattern := range patterns {
+			if _, err = path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("%w: %q", err, pattern)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < d.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range d.jobs {
+				d.record(d.upload(ctx, job))
+			}
+		}()
+	}
+	err = d.dir(ctx, localRoot, ".", parentID)
+	close(d.jobs)
+	wg.Wait()
+
+	sort.Slice(d.results, func(i, j int) bool { return d.results[i].Path < d.results[j].Path })
+	report = &UploadDirReport{Results: d.results}
+	if err == nil && ctx.Err() != nil {
+		err = fmt.Errorf("%w", ctx.Err())
+	}
+	return report, err
+}
+
+type dirUploader struct {
+	u    *Uploader
+	opts UploadDirOptions
+	jobs chan uploadJob
+
+	mu      sync.Mutex
+	results []UploadDirResult
+}
+
+// uploadJob is a local file to upload.
+type uploadJob struct {
+	local    string
+	rel      string
+	parentID int64
+	// existing is the remote file of the same name, if any
+	existing *File
+}
+
+func (d *dirUploader) record(res UploadDirResult) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.results = append(d.results, res)
+}
+
+// dir uploads the contents of the local directory at local into the folder
+// with the given ID. It returns the error of reading either directory; the
+// failures of their contents are recorded.
+func (d *dirUploader) dir(ctx context.Context, local, rel string, parentID int64) error {
+	entries, err := os.ReadDir(local)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	children, _, err := d.u.client.Files.List(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	remote := make(map[string]File, len(children))
+	for _, child := range children {
+		remote[child.Name] = child
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil
+		}
+		name := entry.Name()
+		childRel := path.Join(rel, name)
+		if d.match(d.opts.Exclude, childRel) {
+			continue
+		}
+		existing, exists := remote[name]
+
+		if entry.IsDir() {
+			res := UploadDirResult{Path: childRel, Dir: true}
+			switch {
+			case exists && existing.IsDir():
+				res.FileID = existing.ID
+			case exists:
+				res.Err = fmt.Errorf("%w: a file of the same name exists", ErrNotDir)
+			default:
+				var folder File
+				folder, res.Err = d.u.client.Files.CreateFolder(ctx, name, parentID)
+				res.FileID = folder.ID
+			}
+			if res.Err == nil {
+				res.Err = d.dir(ctx, filepath.Join(local, name), childRel, res.FileID)
+			}
+			d.record(res)
+			continue
+		}
+
+		if !entry.Type().IsRegular() || (len(d.opts.Include) > 0 && !d.match(d.opts.Include, childRel)) {
+			continue
+		}
+		job := uploadJob{local: filepath.Join(local, name), rel: childRel, parentID: parentID}
+		if exists {
+			job.existing = &existing
+		}
+		select {
+		case d.jobs <- job:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// upload uploads a file unless an identical one exists.
+func (d *dirUploader) upload(ctx context.Context, job uploadJob) UploadDirResult {
+	res := UploadDirResult{Path: job.rel}
+	info, err := os.Stat(job.local)
+	if err != nil {
+		res.Err = fmt.Errorf("%w", err)
+		return res
+	}
+	res.Size = info.Size()
+
+	if job.existing != nil {
+		if job.existing.IsDir() {
+			res.Err = fmt.Errorf("%w: a folder of the same name exists", ErrIsDir)
+			return res
+		}
+		same, err := sameFile(job.local, info.Size(), *job.existing)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		if same {
+			res.FileID = job.existing.ID
+			res.Skipped = true
+			return res
+		}
+	}
+
+	result, err := d.u.UploadFile(ctx, job.local, job.parentID)
+	res.FileID = result.FileID
+	res.Err = err
+	return res
+}
+
+// sameFile reports whether the local file at p has the size and checksum of
+// the remote file. Without a remote checksum, the size decides.
+func sameFile(p string, size int64, remote File) (bool, error) {
+	if remote.Size != size {
+		return false, nil
+	}
+	if remote.CRC32 == "" {
+		return true, nil
+	}
+	want, err := parseCRC32(remote.CRC32)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return false, fmt.Errorf("%w", err)
+	}
+	defer f.Close()
+	h := crc32.NewIEEE()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return false, fmt.Errorf("%w", err)
+	}
+	return h.Sum32() == want, nil
+}
+
+// match reports whether the relative path p matches one of the patterns.
+func (d *dirUploader) match(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		name := p
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(p)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
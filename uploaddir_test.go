@@ -0,0 +1,229 @@
+package putio
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// registerTusTree serves tus uploads that add their file to tree once
+// complete.
+func registerTusTree(t *testing.T, tree *fakeTree) {
+	var mu sync.Mutex
+	type upload struct {
+		name   string
+		parent int64
+		length int
+		data   []byte
+	}
+	uploads := make(map[string]*upload)
+
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		testMethod(t, r, http.MethodPost)
+		up := &upload{}
+		up.length, _ = strconv.Atoi(r.Header.Get("Upload-Length"))
+		for _, kv := range strings.Split(r.Header.Get("Upload-Metadata"), ",") {
+			k, v, _ := strings.Cut(kv, " ")
+			b, _ := base64.StdEncoding.DecodeString(v)
+			switch k {
+			case "name":
+				up.name = string(b)
+			case "parent_id":
+				up.parent, _ = strconv.ParseInt(string(b), 10, 64)
+			}
+		}
+		mu.Lock()
+		id := strconv.Itoa(len(uploads))
+		uploads[id] = up
+		mu.Unlock()
+		w.Header().Set("Location", client.TusURL.String()+"u/"+id)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/files/u/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+		mu.Lock()
+		up := uploads[strings.TrimPrefix(r.URL.Path, "/files/u/")]
+		mu.Unlock()
+		body, _ := io.ReadAll(r.Body)
+		up.data = append(up.data, body...)
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(up.data)))
+		if len(up.data) == up.length {
+			tree.mu.Lock()
+			f := tree.add(up.name, up.parent, false)
+			f.Size = int64(len(up.data))
+			f.CRC32 = fmt.Sprintf("%08x", crc32.ChecksumIEEE(up.data))
+			tree.mu.Unlock()
+			w.Header().Set("putio-file-id", strconv.FormatInt(f.ID, 10))
+			w.Header().Set("putio-file-crc32", f.CRC32)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// writeLocalTree creates the files in dir, mapping paths to contents.
+func writeLocalTree(t *testing.T, dir string, files map[string]string) {
+	for p, content := range files {
+		p = filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUploader_UploadDir(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree("sub/", "existing.txt", "changed.txt")
+	tree.register(mux)
+	registerTusTree(t, tree)
+
+	existing, _ := tree.child(0, "existing.txt")
+	existing.Size = int64(len("same"))
+	existing.CRC32 = fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte("same")))
+
+	dir := t.TempDir()
+	writeLocalTree(t, dir, map[string]string{
+		"a.txt":                 "a",
+		"debug.log":             "excluded by name",
+		"existing.txt":          "same",
+		"changed.txt":           "different",
+		"sub/b.txt":             "b",
+		"sub/deep/c.txt":        "c",
+		"node_modules/x/y.js":   "excluded folder",
+		"sub/node_modules/z.js": "excluded folder",
+	})
+
+	u := NewUploader(client)
+	report, err := u.UploadDir(context.Background(), dir, 0, &UploadDirOptions{
+		Workers: 2,
+		Exclude: []string{"*.log", "node_modules"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := report.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, res := range report.Results {
+		s := res.Path
+		if res.Dir {
+			s += "/"
+		}
+		if res.Skipped {
+			s += " (skipped)"
+		}
+		got = append(got, s)
+	}
+	want := []string{"a.txt", "changed.txt", "existing.txt (skipped)", "sub/", "sub/b.txt", "sub/deep/", "sub/deep/c.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+
+	sub, _ := tree.child(0, "sub")
+	deep, ok := tree.child(sub.ID, "deep")
+	if !ok {
+		t.Fatal("sub/deep is not created")
+	}
+	if c, ok := tree.child(deep.ID, "c.txt"); !ok || c.Size != 1 {
+		t.Errorf("got: %+v, %v", c, ok)
+	}
+	if n := tree.count("create-folder"); n != 1 {
+		t.Errorf("got: %v folders created, want: 1", n)
+	}
+	for _, res := range report.Results {
+		if res.FileID == 0 {
+			t.Errorf("%v has no file ID", res.Path)
+		}
+	}
+}
+
+func TestUploader_UploadDir_include(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree()
+	tree.register(mux)
+	registerTusTree(t, tree)
+
+	dir := t.TempDir()
+	writeLocalTree(t, dir, map[string]string{
+		"a.txt":     "a",
+		"b.md":      "b",
+		"sub/c.txt": "c",
+		"sub/d.txt": "d",
+	})
+
+	u := NewUploader(client)
+	report, err := u.UploadDir(context.Background(), dir, 0, &UploadDirOptions{Include: []string{"*.txt"}, Exclude: []string{"sub/d.txt"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, res := range report.Results {
+		got = append(got, res.Path)
+	}
+	want := []string{"a.txt", "sub", "sub/c.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+
+	_, err = u.UploadDir(context.Background(), dir, 0, &UploadDirOptions{Include: []string{"["}})
+	if err == nil {
+		t.Error("malformed pattern is accepted")
+	}
+}
+
+func TestUploader_UploadDir_conflicts(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree("a.txt/", "sub")
+	tree.register(mux)
+	registerTusTree(t, tree)
+
+	dir := t.TempDir()
+	writeLocalTree(t, dir, map[string]string{
+		"a.txt":     "a",
+		"b.txt":     "b",
+		"sub/c.txt": "c",
+	})
+
+	u := NewUploader(client)
+	report, err := u.UploadDir(context.Background(), dir, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed := report.Failed()
+	if len(failed) != 2 || !errors.Is(failed[0].Err, ErrIsDir) || !errors.Is(failed[1].Err, ErrNotDir) {
+		t.Fatalf("got: %+v", failed)
+	}
+	if !errors.Is(report.Err(), ErrIsDir) || !strings.Contains(report.Err().Error(), "sub") {
+		t.Errorf("got: %v", report.Err())
+	}
+	if _, ok := tree.child(0, "b.txt"); !ok {
+		t.Error("b.txt is not uploaded")
+	}
+
+	_, err = u.UploadDir(context.Background(), filepath.Join(dir, "missing"), 0, nil)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got: %v, want: %v", err, os.ErrNotExist)
+	}
+}
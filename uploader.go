@@ -0,0 +1,344 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// defaultChunkSize is used when Uploader.ChunkSize is zero.
+const defaultChunkSize = 8 << 20
+
+// UploadResult is the file created by an Uploader.
+type UploadResult struct {
+	FileID int64
+	CRC32  string
+}
+
+// Uploader uploads files with the tus protocol. It sends a file in chunks
+// and, when a chunk fails, asks the server for the offset it has received and
+// continues from there. An upload that fails for good is terminated on the
+// server. Uploads stopped by the context are left to expire on the server, or
+// to be resumed later through a Store.
+//
+// If the server supports the tus checksum extension, each chunk is sent with
+// an Upload-Checksum header and a chunk the server rejects is sent again. The
+// CRC32 of the file is computed as it is sent; if it differs from the one of
+// the file created, the upload returns both its result and a *ChecksumError.
+type Uploader struct {
+	// ChunkSize is the number of bytes sent with each request. The default
+	// is 8 MiB.
+	ChunkSize int64
+
+	// RetryPolicy decides which failures are retried and how long to wait
+	// before each attempt. Its MaxAttempts limits the failed attempts in a
+	// row; a chunk sent successfully resets the count. Nil uses the
+	// client's RetryPolicy; if that is nil too, the first failure ends the
+	// upload.
+	RetryPolicy *RetryPolicy
+
+	// Overwrite replaces a file of the same name in the target folder.
+	Overwrite bool
+
+	// Progress, if not nil, receives progress reports. Done counts the bytes
+	// sent, and drops back when the server has not received all of them.
+	Progress ProgressFunc
+
+	// Store, if not nil, keeps the sessions of unfinished uploads made with
+	// UploadFile and UploadWithKey, so that they can be resumed after a
+	// restart.
+	Store UploadStore
+
+	client *Client
+}
+
+// NewUploader returns an Uploader that uploads with client.
+func NewUploader(client *Client) *Uploader {
+	return &Uploader{client: client}
+}
+
+// Upload uploads the contents of r as filename into the folder with the
+// given ID. The whole of r is uploaded, regardless of its current offset.
+func (u *Uploader) Upload(ctx context.Context, r io.ReadSeeker, filename string, parentID int64) (UploadResult, error) {
+	return u.UploadWithKey(ctx, r, "", filename, parentID)
+}
+
+// UploadWithKey is like Upload, but saves the session of the upload under key
+// in the Store, and continues the session saved under key by an earlier
+// upload, if any. The key must identify the contents of r. An empty key is
+// never saved.
+func (u *Uploader) UploadWithKey(ctx context.Context, r io.ReadSeeker, key, filename string, parentID int64) (
+	result UploadResult,
+	err error,
+) {
+	ctx, span := u.client.startSpan(ctx, "Uploader.Upload", attrParentID.Int64(parentID))
+	defer func() { endSpan(span, err) }()
+
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("%w", err)
+	}
+
+	location, offset, ok := u.restore(ctx, key, size)
+	for attempt := 1; !ok; attempt++ {
+		location, err = u.client.Upload.CreateUpload(ctx, filename, parentID, size, u.Overwrite)
+		if err == nil {
+			u.save(ctx, key, UploadSession{Location: location, Size: size, CreatedAt: time.Now()})
+			break
+		}
+		if !u.wait(ctx, err, attempt) {
+			if ctx.Err() != nil {
+				return UploadResult{}, fmt.Errorf("%w", ctx.Err())
+			}
+			return UploadResult{}, err
+		}
+	}
+
+	tracker := newProgressTracker(u.Progress, size, offset)
+	result, err = u.resume(ctx, r, location, size, offset, tracker)
+	tracker.finish()
+	// keep the session only if the upload may be resumed later
+	if err == nil || ctx.Err() == nil {
+		u.forget(ctx, key)
+	}
+	return result, err
+}
+
+// UploadFile uploads the local file at path into the folder with the given
+// ID, keeping its name. The upload is saved in the Store under UploadKey, so
+// an interrupted upload of the same file continues where it stopped.
+func (u *Uploader) UploadFile(ctx context.Context, path string, parentID int64) (UploadResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("%w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("%w", err)
+	}
+	return u.UploadWithKey(ctx, f, UploadKey(path, info, parentID), filepath.Base(path), parentID)
+}
+
+// restore returns the location and offset of the session saved under key.
+// It reports false if there is no session that can be continued.
+func (u *Uploader) restore(ctx context.Context, key string, size int64) (string, int64, bool) {
+	if u.Store == nil || key == "" {
+		return "", 0, false
+	}
+	s, ok, err := u.Store.Load(key)
+	if err != nil {
+		u.client.Upload.log(ctx, "cannot load upload session", "key", key, "error", err)
+		return "", 0, false
+	}
+	if !ok || s.Size != size {
+		return "", 0, false
+	}
+
+	offset, err := u.client.Upload.GetOffset(ctx, s.Location)
+	if err != nil {
+		// most likely expired on the server
+		u.client.Upload.log(ctx, "cannot resume upload", "location", s.Location, "error", err)
+		u.forget(ctx, key)
+		return "", 0, false
+	}
+	u.client.Upload.log(ctx, "resuming upload", "location", s.Location, "offset", offset)
+	return s.Location, offset, true
+}
+
+// save saves s under key. Failing to do so only makes the upload impossible
+// to resume, so the error is logged.
+func (u *Uploader) save(ctx context.Context, key string, s UploadSession) {
+	if u.Store == nil || key == "" {
+		return
+	}
+	err := u.Store.Save(key, s)
+	if err != nil {
+		u.client.Upload.log(ctx, "cannot save upload session", "key", key, "error", err)
+	}
+}
+
+func (u *Uploader) forget(ctx context.Context, key string) {
+	if u.Store == nil || key == "" {
+		return
+	}
+	err := u.Store.Delete(key)
+	if err != nil {
+		u.client.Upload.log(ctx, "cannot delete upload session", "key", key, "error", err)
+	}
+}
+
+// resume sends r from offset to the upload at location until it is
+// complete.
+func (u *Uploader) resume(
+	ctx context.Context,
+	r io.ReadSeeker,
+	location string,
+	size, offset int64,
+	tracker *progressTracker,
+) (
+	UploadResult,
+	error,
+) {
+	chunkSize := u.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	var crc runningCRC32
+	synced := true
+	for attempt := 1; ; attempt++ {
+		var err error
+		if !synced {
+			offset, err = u.client.Upload.GetOffset(ctx, location)
+			synced = err == nil
+			if synced {
+				tracker.set(offset)
+			}
+		}
+		if err == nil {
+			var result sendResult
+			result, err = u.sendChunk(ctx, r, location, offset, chunkSize, size, &crc, tracker)
+			if err == nil && result.fileID != "" {
+				return u.result(r, &crc, size, result)
+			}
+			if err == nil {
+				offset = result.offset
+				tracker.set(offset)
+				attempt = 0
+				continue
+			}
+			synced = false
+		}
+
+		var seekErr *seekError
+		if errors.As(err, &seekErr) || !u.wait(ctx, err, attempt) {
+			if ctx.Err() != nil {
+				return UploadResult{}, fmt.Errorf("%w", ctx.Err())
+			}
+			_ = u.client.Upload.TerminateUpload(ctx, location)
+			return UploadResult{}, err
+		}
+	}
+}
+
+// sendChunk sends the chunk of r at offset and returns the response with the
+// new offset. The bytes read are added to crc.
+func (u *Uploader) sendChunk(
+	ctx context.Context,
+	r io.ReadSeeker,
+	location string,
+	offset, chunkSize, size int64,
+	crc *runningCRC32,
+	tracker *progressTracker,
+) (sendResult, error) {
+	err := crc.catchUp(r, offset)
+	if err != nil {
+		return sendResult{}, &seekError{err}
+	}
+	_, err = r.Seek(offset, io.SeekStart)
+	if err != nil {
+		return sendResult{}, &seekError{err}
+	}
+	n := size - offset
+	if n > chunkSize {
+		n = chunkSize
+	}
+	checksum, err := u.client.Upload.chunkChecksum(ctx, r, n)
+	if err != nil {
+		return sendResult{}, &seekError{err}
+	}
+
+	var body io.Reader = io.LimitReader(r, n)
+	var cr *crcReader
+	if offset == crc.n {
+		cr = &crcReader{r: body}
+		body = cr
+	}
+	u.client.Upload.log(ctx, "sending chunk", "location", location, "offset", offset, "length", n)
+	result, err := u.client.Upload.send(ctx, &trackedReader{r: body, tracker: tracker}, location, offset, checksum)
+	if cr != nil {
+		crc.add(cr)
+	}
+	if err != nil {
+		return sendResult{}, err
+	}
+	if result.offset < 0 {
+		result.offset = offset + n
+	}
+	if result.fileID == "" && result.offset >= size {
+		return sendResult{}, fmt.Errorf("%w: upload is complete without a file ID", ErrUnexpected)
+	}
+	return result, nil
+}
+
+// result returns the result of the complete upload of r and compares the
+// checksum of r with the one of the file created.
+func (u *Uploader) result(r io.ReadSeeker, crc *runningCRC32, size int64, sent sendResult) (UploadResult, error) {
+	id, err := strconv.ParseInt(sent.fileID, 10, 64)
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("cannot parse putio-file-id header: %w", err)
+	}
+	result := UploadResult{FileID: id, CRC32: sent.crc32}
+	err = crc.catchUp(r, size)
+	if err != nil {
+		return result, &seekError{err}
+	}
+	return result, checkCRC32(crc.sum, sent.crc32)
+}
+
+// wait reports whether the given failed attempt is retried and sleeps until
+// the next one.
+func (u *Uploader) wait(ctx context.Context, err error, attempt int) bool {
+	policy := u.RetryPolicy
+	if policy == nil {
+		policy = u.client.RetryPolicy
+	}
+	if policy == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+
+	var resp *http.Response
+	var er *ErrorResponse
+	if errors.As(err, &er) {
+		resp = er.Response
+	}
+	retryable := policy.retryable
+	if policy.ShouldRetry != nil {
+		retryable = policy.ShouldRetry
+	}
+	// a conflict means the offset is out of sync, a rejected checksum that
+	// the chunk was corrupted on the way, and a cancellation while ctx is
+	// alive that the chunk stalled; the next attempt fixes all of them
+	var ce *ChecksumError
+	conflict := resp != nil && resp.StatusCode == http.StatusConflict
+	stalled := errors.Is(err, ErrUploadStalled) || errors.Is(err, context.Canceled)
+	if !conflict && !stalled && !errors.As(err, &ce) && !retryable(resp, err) {
+		return false
+	}
+
+	delay, ok := retryAfter(resp)
+	if !ok {
+		delay = policy.Backoff(attempt)
+	} else if policy.MaxRetryAfter > 0 && delay > policy.MaxRetryAfter {
+		return false
+	}
+	u.client.Upload.log(ctx, "retrying upload", "attempt", attempt, "delay", delay.Round(time.Millisecond), "error", err)
+	return sleep(ctx, delay) == nil
+}
+
+// seekError is an error of the uploaded io.ReadSeeker, which is never
+// retried.
+type seekError struct {
+	err error
+}
+
+func (e *seekError) Error() string { return e.err.Error() }
+func (e *seekError) Unwrap() error { return e.err }
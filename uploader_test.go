@@ -0,0 +1,302 @@
+package putio
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tusServer is an in-memory tus server for a single upload.
+type tusServer struct {
+	mu         sync.Mutex
+	data       []byte
+	length     int64
+	creates    int
+	patches    int
+	heads      int
+	terminated bool
+	// fail is called for each PATCH with its number, starting from 1. A
+	// non-zero status fails the request after half of its body is stored.
+	fail func(patch int) int
+	// checksum enables the checksum extension with sha1.
+	checksum  bool
+	checksums int
+	// corrupt is the number of a PATCH whose body is altered on its way.
+	corrupt int
+	// stall is the number of a PATCH that is never answered; its body is
+	// discarded.
+	stall int
+	// crc32, if not empty, is reported instead of the checksum of the data.
+	crc32 string
+}
+
+func (s *tusServer) register(t *testing.T) {
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Tus-Version", "1.0.0")
+			w.Header().Set("Tus-Extension", "creation,termination")
+			if s.checksum {
+				w.Header().Set("Tus-Extension", "creation,checksum,termination")
+				w.Header().Set("Tus-Checksum-Algorithm", "sha1")
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		testMethod(t, r, http.MethodPost)
+		s.creates++
+		s.length, _ = strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+		w.Header().Set("Location", client.TusURL.String()+"up")
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/files/up", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.Method {
+		case http.MethodHead:
+			s.heads++
+			w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+		case http.MethodDelete:
+			s.terminated = true
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodPatch:
+			s.patches++
+			offset, _ := strconv.Atoi(r.Header.Get("Upload-Offset"))
+			if offset != len(s.data) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			if s.patches == s.stall {
+				s.mu.Unlock()
+				<-r.Context().Done()
+				s.mu.Lock()
+				return
+			}
+			if s.patches == s.corrupt {
+				body[0]++
+			}
+			if s.checksum {
+				s.checksums++
+				sum := sha1.Sum(body)
+				if r.Header.Get("Upload-Checksum") != "sha1 "+base64.StdEncoding.EncodeToString(sum[:]) {
+					w.WriteHeader(statusChecksumMismatch)
+					return
+				}
+			}
+			if s.fail != nil {
+				if status := s.fail(s.patches); status != 0 {
+					s.data = append(s.data, body[:len(body)/2]...)
+					w.WriteHeader(status)
+					return
+				}
+			}
+			s.data = append(s.data, body...)
+			w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+			if int64(len(s.data)) == s.length {
+				w.Header().Set("putio-file-id", "77")
+				w.Header().Set("putio-file-crc32", fmt.Sprintf("%08x", crc32.ChecksumIEEE(s.data)))
+				if s.crc32 != "" {
+					w.Header().Set("putio-file-crc32", s.crc32)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+func TestUploader_Upload(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &tusServer{}
+	server.register(t)
+
+	u := NewUploader(client)
+	u.ChunkSize = 4
+	content := "hello, uploader"
+	result, err := u.Upload(context.Background(), strings.NewReader(content), "hello.txt", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.FileID != 77 || result.CRC32 != fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(content))) {
+		t.Errorf("got: %+v", result)
+	}
+	if string(server.data) != content {
+		t.Errorf("got: %q, want: %q", server.data, content)
+	}
+	if server.patches != 4 {
+		t.Errorf("got: %v chunks, want: 4", server.patches)
+	}
+}
+
+func TestUploader_Upload_resume(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &tusServer{fail: func(patch int) int {
+		if patch == 2 || patch == 3 {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}}
+	server.register(t)
+
+	u := NewUploader(client)
+	u.ChunkSize = 8
+	u.RetryPolicy = fastRetryPolicy()
+	content := "0123456789abcdefghijklmnopqrstuvwxyz"
+	_, err := u.Upload(context.Background(), strings.NewReader(content), "file", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(server.data) != content {
+		t.Errorf("got: %q, want: %q", server.data, content)
+	}
+	if server.heads != 2 {
+		t.Errorf("got: %v offset requests, want: 2", server.heads)
+	}
+}
+
+func TestUploader_Upload_permanentFailure(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &tusServer{fail: func(patch int) int { return http.StatusForbidden }}
+	server.register(t)
+
+	u := NewUploader(client)
+	u.RetryPolicy = fastRetryPolicy()
+	_, err := u.Upload(context.Background(), strings.NewReader("content"), "file", 0)
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("got: %v, want: %v", err, ErrForbidden)
+	}
+	if server.patches != 1 || !server.terminated {
+		t.Errorf("got: %v patches, terminated: %v", server.patches, server.terminated)
+	}
+}
+
+func TestUploader_Upload_retriesExhausted(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &tusServer{fail: func(patch int) int { return http.StatusBadGateway }}
+	server.register(t)
+
+	u := NewUploader(client)
+	u.RetryPolicy = fastRetryPolicy()
+	u.ChunkSize = 2
+	_, err := u.Upload(context.Background(), strings.NewReader("content"), "file", 0)
+	if !errors.Is(err, ErrServerError) {
+		t.Errorf("got: %v, want: %v", err, ErrServerError)
+	}
+	if server.patches != 3 || !server.terminated {
+		t.Errorf("got: %v patches, terminated: %v", server.patches, server.terminated)
+	}
+}
+
+func TestUploader_UploadFile(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &tusServer{}
+	server.register(t)
+
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	err := os.WriteFile(path, []byte("notes"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewUploader(client).UploadFile(context.Background(), path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.FileID != 77 || string(server.data) != "notes" {
+		t.Errorf("got: %+v %q", result, server.data)
+	}
+}
+
+func TestUploader_Upload_checksum(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &tusServer{checksum: true, corrupt: 2}
+	server.register(t)
+
+	u := NewUploader(client)
+	u.ChunkSize = 4
+	u.RetryPolicy = fastRetryPolicy()
+	content := "hello, uploader"
+	_, err := u.Upload(context.Background(), strings.NewReader(content), "hello.txt", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(server.data) != content {
+		t.Errorf("got: %q, want: %q", server.data, content)
+	}
+	// the corrupted chunk is sent again
+	if server.checksums != 5 {
+		t.Errorf("got: %v checked chunks, want: 5", server.checksums)
+	}
+}
+
+func TestUploader_Upload_crc32Mismatch(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &tusServer{crc32: "00000000", fail: func(patch int) int {
+		if patch == 2 {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}}
+	server.register(t)
+
+	u := NewUploader(client)
+	u.ChunkSize = 4
+	u.RetryPolicy = fastRetryPolicy()
+	result, err := u.Upload(context.Background(), strings.NewReader("hello, uploader"), "hello.txt", 0)
+	var ce *ChecksumError
+	if !errors.As(err, &ce) || !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("got: %v, want a *ChecksumError", err)
+	}
+	if want := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte("hello, uploader"))); ce.Local != want || ce.Remote != "00000000" {
+		t.Errorf("got: %+v, want local %v", ce, want)
+	}
+	if result.FileID != 77 {
+		t.Errorf("got file ID: %v, want: 77", result.FileID)
+	}
+}
+
+func TestUploader_Upload_stall(t *testing.T) {
+	setup()
+	defer teardown()
+	server := &tusServer{stall: 1}
+	server.register(t)
+
+	client.Timeout = 50 * time.Millisecond
+	u := NewUploader(client)
+	u.ChunkSize = 4
+	u.RetryPolicy = fastRetryPolicy()
+	content := "hello, uploader"
+	_, err := u.Upload(context.Background(), strings.NewReader(content), "hello.txt", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.terminated {
+		t.Error("stalled upload is terminated")
+	}
+	if string(server.data) != content {
+		t.Errorf("got: %q, want: %q", server.data, content)
+	}
+	if server.patches != 5 {
+		t.Errorf("got: %v patches, want: 5", server.patches)
+	}
+}
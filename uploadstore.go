@@ -0,0 +1,186 @@
+package putio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// defaultUploadTTL is used when the TTL of an upload store is zero.
+const defaultUploadTTL = 24 * time.Hour
+
+// UploadSession is an unfinished tus upload.
+type UploadSession struct {
+	// Location is the URL of the upload returned by CreateUpload.
+	Location string `json:"location"`
+	// Size is the length of the upload.
+	Size int64 `json:"size"`
+	// CreatedAt is the time the upload was created.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UploadStore keeps the sessions of unfinished uploads, so that an Uploader
+// in another process can resume them. Keys are made with UploadKey or by the
+// caller of Uploader.UploadWithKey. Implementations must be safe for
+// concurrent use.
+type UploadStore interface {
+	// Load returns the session saved under key. Expired sessions are not
+	// returned.
+	Load(key string) (UploadSession, bool, error)
+	// Save saves the session under key.
+	Save(key string, s UploadSession) error
+	// Delete removes the session saved under key, if any.
+	Delete(key string) error
+}
+
+// UploadKey returns the key of the upload of the local file at path, with
+// the given file info, into the folder with the given ID. A file that is
+// modified gets a new key, so its upload starts over.
+func UploadKey(path string, info fs.FileInfo, parentID int64) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return fmt.Sprintf("%s:%d:%d:%d", path, info.Size(), info.ModTime().UnixNano(), parentID)
+}
+
+// expired reports whether s is older than ttl.
+func (s UploadSession) expired(ttl time.Duration) bool {
+	if ttl <= 0 {
+		ttl = defaultUploadTTL
+	}
+	return time.Since(s.CreatedAt) > ttl
+}
+
+// MemoryUploadStore is an UploadStore that keeps sessions in memory. It lets
+// uploads resume within a process only.
+type MemoryUploadStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]UploadSession
+}
+
+// NewMemoryUploadStore returns an empty MemoryUploadStore whose sessions
+// expire after ttl. Zero ttl means 24 hours.
+func NewMemoryUploadStore(ttl time.Duration) *MemoryUploadStore {
+	return &MemoryUploadStore{ttl: ttl, sessions: make(map[string]UploadSession)}
+}
+
+// Load implements UploadStore.
+func (m *MemoryUploadStore) Load(key string) (UploadSession, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[key]
+	if ok && s.expired(m.ttl) {
+		delete(m.sessions, key)
+		return UploadSession{}, false, nil
+	}
+	return s, ok, nil
+}
+
+// Save implements UploadStore.
+func (m *MemoryUploadStore) Save(key string, s UploadSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range m.sessions {
+		if v.expired(m.ttl) {
+			delete(m.sessions, k)
+		}
+	}
+	m.sessions[key] = s
+	return nil
+}
+
+// Delete implements UploadStore.
+func (m *MemoryUploadStore) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, key)
+	return nil
+}
+
+// FileUploadStore is an UploadStore that keeps sessions in a JSON file. The
+// file is read and replaced on every call, so processes may share it as long
+// as they do not write at the same time.
+type FileUploadStore struct {
+	mu   sync.Mutex
+	path string
+	ttl  time.Duration
+}
+
+// NewFileUploadStore returns a FileUploadStore that keeps sessions in the
+// file at path, which is created when the first session is saved. Sessions
+// expire after ttl; zero ttl means 24 hours.
+func NewFileUploadStore(path string, ttl time.Duration) *FileUploadStore {
+	return &FileUploadStore{path: path, ttl: ttl}
+}
+
+// Load implements UploadStore.
+func (f *FileUploadStore) Load(key string) (UploadSession, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sessions, err := f.read()
+	if err != nil {
+		return UploadSession{}, false, err
+	}
+	s, ok := sessions[key]
+	if ok && s.expired(f.ttl) {
+		return UploadSession{}, false, nil
+	}
+	return s, ok, nil
+}
+
+// Save implements UploadStore. Expired sessions are removed from the file.
+func (f *FileUploadStore) Save(key string, s UploadSession) error {
+	return f.update(func(sessions map[string]UploadSession) {
+		sessions[key] = s
+	})
+}
+
+// Delete implements UploadStore.
+func (f *FileUploadStore) Delete(key string) error {
+	return f.update(func(sessions map[string]UploadSession) {
+		delete(sessions, key)
+	})
+}
+
+func (f *FileUploadStore) update(fn func(map[string]UploadSession)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sessions, err := f.read()
+	if err != nil {
+		return err
+	}
+	for k, v := range sessions {
+		if v.expired(f.ttl) {
+			delete(sessions, k)
+		}
+	}
+	fn(sessions)
+
+	b, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return writeFileAtomic(f.path, b)
+}
+
+func (f *FileUploadStore) read() (map[string]UploadSession, error) {
+	sessions := make(map[string]UploadSession)
+	b, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	err = json.Unmarshal(b, &sessions)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return sessions, nil
+}
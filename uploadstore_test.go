@@ -0,0 +1,115 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testUploadStore(t *testing.T, store UploadStore) {
+	t.Helper()
+	s := UploadSession{Location: "http://tus/1", Size: 10, CreatedAt: time.Now()}
+	if err := store.Save("a", s); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := store.Load("a")
+	if err != nil || !ok || got.Location != s.Location || got.Size != 10 {
+		t.Errorf("got: %+v %v %v", got, ok, err)
+	}
+
+	if err = store.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ = store.Load("a"); ok {
+		t.Error("deleted session is loaded")
+	}
+
+	s.CreatedAt = time.Now().Add(-2 * time.Hour)
+	if err = store.Save("old", s); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ = store.Load("old"); ok {
+		t.Error("expired session is loaded")
+	}
+}
+
+func TestMemoryUploadStore(t *testing.T) {
+	testUploadStore(t, NewMemoryUploadStore(time.Hour))
+}
+
+func TestFileUploadStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uploads.json")
+	testUploadStore(t, NewFileUploadStore(path, time.Hour))
+
+	// sessions survive a new store
+	err := NewFileUploadStore(path, time.Hour).Save("b", UploadSession{Location: "http://tus/2", CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok, err := NewFileUploadStore(path, time.Hour).Load("b")
+	if err != nil || !ok || s.Location != "http://tus/2" {
+		t.Errorf("got: %+v %v %v", s, ok, err)
+	}
+}
+
+func TestUploader_UploadFile_resumeSession(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := &tusServer{fail: func(patch int) int {
+		if patch == 2 {
+			// the process stops in the middle of the second chunk
+			cancel()
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}}
+	server.register(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "video.mkv")
+	content := "0123456789abcdefghijklmnopqrstuvwxyz"
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storePath := filepath.Join(dir, "uploads.json")
+
+	u := NewUploader(client)
+	u.ChunkSize = 10
+	u.RetryPolicy = fastRetryPolicy()
+	u.Store = NewFileUploadStore(storePath, 0)
+	_, err = u.UploadFile(ctx, path, 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got: %v, want: %v", err, context.Canceled)
+	}
+	if server.terminated {
+		t.Fatal("canceled upload is terminated")
+	}
+
+	// a new process with a new store
+	u = NewUploader(client)
+	u.ChunkSize = 10
+	u.Store = NewFileUploadStore(storePath, 0)
+	result, err := u.UploadFile(context.Background(), path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.FileID != 77 || string(server.data) != content {
+		t.Errorf("got: %+v %q", result, server.data)
+	}
+	if server.creates != 1 || server.heads != 1 {
+		t.Errorf("got: %v creates and %v offset requests, want: 1 and 1", server.creates, server.heads)
+	}
+
+	info, _ := os.Stat(path)
+	if _, ok, _ := u.Store.Load(UploadKey(path, info, 0)); ok {
+		t.Error("finished upload is still saved")
+	}
+}
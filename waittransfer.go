@@ -0,0 +1,102 @@
+package putio
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultWaitMinInterval is used when WaitOptions.MinInterval is zero.
+	defaultWaitMinInterval = time.Second
+
+	// defaultWaitMaxInterval is used when WaitOptions.MaxInterval is zero.
+	defaultWaitMaxInterval = 30 * time.Second
+)
+
+// WaitOptions configure TransfersService.WaitForTransfer.
+type WaitOptions struct {
+	// MinInterval is the time after the first poll, which is made right
+	// away, and after a poll that shows progress. The default is 1 second.
+	MinInterval time.Duration
+
+	// MaxInterval caps the time between two polls, which doubles after
+	// each poll without progress. The default is 30 seconds.
+	MaxInterval time.Duration
+
+	// Retries is the number of times a failed transfer is retried with
+	// TransfersService.Retry before WaitForTransfer gives up.
+	Retries int
+
+	// OnUpdate, if not nil, is called with every snapshot of the transfer.
+	OnUpdate func(Transfer)
+}
+
+// WaitForTransfer polls the transfer with the given ID until it has finished
+// downloading, and returns its final state, which holds the FileID of the
+// download. A transfer that fails is retried up to opts.Retries times; the
+// last failure is returned as a *TransferError. Other errors, such as the one
+// of ctx, are returned with the last snapshot of the transfer, if any. Nil
+// opts is equivalent to the zero WaitOptions.
+func (t *TransfersService) WaitForTransfer(ctx context.Context, id int64, opts *WaitOptions) (Transfer, error) {
+	ctx, span := t.client.startSpan(ctx, "TransfersService.WaitForTransfer", attrTransferID.Int64(id))
+	defer span.End()
+
+	var o WaitOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.MinInterval <= 0 {
+		o.MinInterval = defaultWaitMinInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = defaultWaitMaxInterval
+	}
+	if o.MaxInterval < o.MinInterval {
+		o.MaxInterval = o.MinInterval
+	}
+
+	var prev *Transfer
+	interval := o.MinInterval
+	for retries := 0; ; {
+		tr, err := t.Get(ctx, id)
+		if err != nil {
+			if prev != nil {
+				return *prev, err
+			}
+			return Transfer{}, err
+		}
+		if o.OnUpdate != nil {
+			o.OnUpdate(tr)
+		}
+
+		switch {
+		case tr.Status.IsCompleted():
+			return tr, nil
+		case tr.Status.IsFailed() && retries >= o.Retries:
+			return tr, &TransferError{Transfer: tr, Retries: retries}
+		case tr.Status.IsFailed():
+			retries++
+			var retried Transfer
+			retried, err = t.Retry(ctx, id)
+			if err != nil {
+				return tr, err
+			}
+			tr = retried
+			interval = o.MinInterval
+		case prev != nil && (prev.Status != tr.Status || transferProgressed(*prev, tr)):
+			interval = o.MinInterval
+		case prev != nil:
+			interval *= 2
+			if interval > o.MaxInterval {
+				interval = o.MaxInterval
+			}
+		}
+		prev = &tr
+
+		err = sleep(ctx, interval)
+		if err != nil {
+			return tr, fmt.Errorf("%w", err)
+		}
+	}
+}
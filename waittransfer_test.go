@@ -0,0 +1,129 @@
+package putio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// serveTransfer serves the given states of transfer 1 in turn, repeating the
+// last one. It returns the number of retries.
+func serveTransfer(t *testing.T, states ...Transfer) func() int {
+	var mu sync.Mutex
+	var polls, retries int
+	mux.HandleFunc("/v2/transfers/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		mu.Lock()
+		defer mu.Unlock()
+		state := states[len(states)-1]
+		if polls < len(states) {
+			state = states[polls]
+		}
+		polls++
+		state.ID = 1
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"transfer": state})
+	})
+	mux.HandleFunc("/v2/transfers/retry", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		mu.Lock()
+		defer mu.Unlock()
+		retries++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"transfer": Transfer{ID: 1, Status: TransferStatusInQueue}})
+	})
+	return func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return retries
+	}
+}
+
+func fastWaitOptions() *WaitOptions {
+	return &WaitOptions{MinInterval: time.Millisecond, MaxInterval: 4 * time.Millisecond}
+}
+
+func TestTransfers_WaitForTransfer(t *testing.T) {
+	setup()
+	defer teardown()
+	serveTransfer(t,
+		Transfer{Status: TransferStatusInQueue},
+		Transfer{Status: TransferStatusDownloading, PercentDone: 50},
+		Transfer{Status: TransferStatusDownloading, PercentDone: 50},
+		Transfer{Status: TransferStatusSeeding, PercentDone: 100, FileID: 42},
+	)
+
+	opts := fastWaitOptions()
+	var updates int
+	opts.OnUpdate = func(Transfer) { updates++ }
+	tr, err := client.Transfers.WaitForTransfer(context.Background(), 1, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.FileID != 42 {
+		t.Errorf("got file ID: %v, want: 42", tr.FileID)
+	}
+	if updates != 4 {
+		t.Errorf("got: %v updates, want: 4", updates)
+	}
+}
+
+func TestTransfers_WaitForTransfer_error(t *testing.T) {
+	setup()
+	defer teardown()
+	retries := serveTransfer(t,
+		Transfer{Status: TransferStatusDownloading},
+		Transfer{Status: TransferStatusError, ErrorMessage: "tracker is down"},
+	)
+
+	opts := fastWaitOptions()
+	opts.Retries = 2
+	_, err := client.Transfers.WaitForTransfer(context.Background(), 1, opts)
+	var te *TransferError
+	if !errors.As(err, &te) || !errors.Is(err, ErrTransferFailed) {
+		t.Fatalf("got: %v, want a *TransferError", err)
+	}
+	if te.Transfer.ErrorMessage != "tracker is down" || te.Retries != 2 {
+		t.Errorf("got: %+v", te)
+	}
+	if n := retries(); n != 2 {
+		t.Errorf("got: %v retries, want: 2", n)
+	}
+}
+
+func TestTransfers_WaitForTransfer_retry(t *testing.T) {
+	setup()
+	defer teardown()
+	retries := serveTransfer(t,
+		Transfer{Status: TransferStatusError},
+		Transfer{Status: TransferStatusCompleted, FileID: 42},
+	)
+
+	opts := fastWaitOptions()
+	opts.Retries = 1
+	tr, err := client.Transfers.WaitForTransfer(context.Background(), 1, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.FileID != 42 || retries() != 1 {
+		t.Errorf("got: %+v after %v retries", tr, retries())
+	}
+}
+
+func TestTransfers_WaitForTransfer_cancel(t *testing.T) {
+	setup()
+	defer teardown()
+	serveTransfer(t, Transfer{Status: TransferStatusDownloading})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	tr, err := client.Transfers.WaitForTransfer(ctx, 1, fastWaitOptions())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got: %v, want: %v", err, context.DeadlineExceeded)
+	}
+	if tr.Status != TransferStatusDownloading {
+		t.Errorf("got: %+v", tr)
+	}
+}
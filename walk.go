@@ -0,0 +1,225 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// SkipDir and SkipAll are returned by a WalkFunc to skip the current folder
+// or the rest of the walk. They are the same values as fs.SkipDir and
+// fs.SkipAll.
+var (
+	SkipDir = fs.SkipDir
+	SkipAll = fs.SkipAll
+)
+
+// defaultWalkConcurrency is used when WalkOptions.Concurrency is zero.
+const defaultWalkConcurrency = 4
+
+// WalkFunc is called by Walk for each file and folder, like fs.WalkDirFunc.
+// The path is relative to the walked folder, which itself is ".".
+//
+// If listing a folder fails, the function is called a second time for that
+// folder with the error. Returning SkipDir from a folder skips its contents;
+// from a file, it skips the remaining files of its folder. Returning SkipAll
+// stops the walk. Any other error stops the walk and is returned by Walk.
+type WalkFunc func(path string, file File, err error) error
+
+// WalkOptions configure WalkWithOptions.
+type WalkOptions struct {
+	// Concurrency is the maximum number of folders listed at the same time.
+	// The next Concurrency-1 folders of the folder being walked are listed
+	// ahead, so a value above one makes the walk faster at the cost of
+	// listing folders that are later skipped. The default is 4.
+	Concurrency int
+
+	// Sorted visits the files of each folder in lexical order of their
+	// names, like filepath.WalkDir. Otherwise the order of the listing is
+	// kept.
+	Sorted bool
+
+	// List are the options of each folder listing.
+	List *ListOptions
+}
+
+// Walk walks the tree rooted at the folder with the given ID, calling fn for
+// each file and folder, including the root. The function is never called
+// concurrently; folders are visited depth first, parents before their
+// children.
+func (f *FilesService) Walk(ctx context.Context, id int64, fn WalkFunc) error {
+	return f.WalkWithOptions(ctx, id, nil, fn)
+}
+
+// WalkWithOptions is like Walk with options. Nil opts is equivalent to the
+// zero WalkOptions.
+func (f *FilesService) WalkWithOptions(ctx context.Context, id int64, opts *WalkOptions, fn WalkFunc) error {
+	ctx, span := f.client.startSpan(ctx, "FilesService.Walk", attrFileID.Int64(id))
+	defer span.End()
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &walker{files: f, ctx: ctx, fn: fn}
+	if opts != nil {
+		w.opts = *opts
+	}
+	concurrency := w.opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultWalkConcurrency
+	}
+	// the walk itself lists the folder it waits for, the rest is listed
+	// ahead in the background
+	if concurrency > 1 {
+		w.sem = make(chan struct{}, concurrency-1)
+	}
+	defer func() {
+		cancel()
+		w.wg.Wait()
+	}()
+
+	root, err := f.Get(ctx, id)
+	if err != nil {
+		err = fn(".", File{ID: id}, err)
+	} else {
+		err = w.walk(".", root, nil)
+	}
+	if errors.Is(err, SkipDir) || errors.Is(err, SkipAll) {
+		return nil
+	}
+	return err
+}
+
+type walker struct {
+	files *FilesService
+	ctx   context.Context
+	opts  WalkOptions
+	fn    WalkFunc
+
+	// sem bounds the background listings, nil disables them
+	sem chan struct{}
+	wg  sync.WaitGroup
+}
+
+// walk visits dir and everything below it. l is the listing of dir started
+// ahead, if any.
+func (w *walker) walk(p string, dir File, l *listing) error {
+	err := w.fn(p, dir, nil)
+	if err != nil || !dir.IsDir() {
+		l.cancel()
+		if errors.Is(err, SkipDir) && dir.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	files, err := w.wait(dir.ID, l)
+	if err != nil {
+		err = w.fn(p, dir, err)
+		if err != nil {
+			if errors.Is(err, SkipDir) {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	// only the next few sibling folders are listed ahead, so that the
+	// listings waiting for the walk to reach them stay few
+	listings := make([]*listing, len(files))
+	next, ahead := 0, 0
+	for i, file := range files {
+		if listings[i] != nil {
+			ahead--
+		}
+		next = max(next, i+1)
+		for ; next < len(files) && ahead < cap(w.sem); next++ {
+			if files[next].IsDir() {
+				listings[next] = w.prefetch(files[next].ID)
+				ahead++
+			}
+		}
+		err = w.walk(path.Join(p, file.Name), file, listings[i])
+		if err != nil {
+			for _, l := range listings[i+1:] {
+				l.cancel()
+			}
+			if errors.Is(err, SkipDir) {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// list returns the children of the folder with the given ID.
+func (w *walker) list(id int64) ([]File, error) {
+	var files []File
+	it := w.files.Iterate(w.ctx, id, w.opts.List)
+	for it.Next() {
+		files = append(files, it.File())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if w.opts.Sorted {
+		sort.SliceStable(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	}
+	return files, nil
+}
+
+// prefetch starts listing the folder with the given ID in the background.
+func (w *walker) prefetch(id int64) *listing {
+	l := &listing{done: make(chan struct{})}
+	if w.sem == nil {
+		return l
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		select {
+		case w.sem <- struct{}{}:
+		case <-w.ctx.Done():
+			return
+		}
+		defer func() { <-w.sem }()
+		if l.claim() {
+			l.files, l.err = w.list(id)
+			close(l.done)
+		}
+	}()
+	return l
+}
+
+// wait returns the listing of the folder with the given ID. A listing that
+// has not been started in the background is done right away.
+func (w *walker) wait(id int64, l *listing) ([]File, error) {
+	if l == nil || l.claim() {
+		return w.list(id)
+	}
+	<-l.done
+	return l.files, l.err
+}
+
+// listing is the result of a folder listing started ahead of the walk.
+type listing struct {
+	// claimed is set by whoever lists the folder first
+	claimed int32
+	done    chan struct{}
+	files   []File
+	err     error
+}
+
+func (l *listing) claim() bool {
+	return atomic.CompareAndSwapInt32(&l.claimed, 0, 1)
+}
+
+// cancel prevents a listing that is not started yet from being made.
+func (l *listing) cancel() {
+	if l != nil {
+		l.claim()
+	}
+}
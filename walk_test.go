@@ -0,0 +1,164 @@
+package putio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func walkPaths(t *testing.T, opts *WalkOptions, fn WalkFunc) []string {
+	t.Helper()
+	var paths []string
+	err := client.Files.WalkWithOptions(context.Background(), 0, opts, func(p string, file File, err error) error {
+		if err != nil {
+			return err
+		}
+		paths = append(paths, p)
+		if fn != nil {
+			return fn(p, file, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return paths
+}
+
+func TestFiles_Walk(t *testing.T) {
+	setup()
+	defer teardown()
+	newFakeTree("/b/y.txt", "/b/x/z.txt", "/a.txt", "/c/").register(mux)
+
+	want := []string{".", "a.txt", "b", "b/x", "b/x/z.txt", "b/y.txt", "c"}
+	for _, concurrency := range []int{1, 2, 8} {
+		got := walkPaths(t, &WalkOptions{Concurrency: concurrency, Sorted: true}, nil)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("concurrency %d: got: %v, want: %v", concurrency, got, want)
+		}
+	}
+
+	// listing order is kept unless sorted
+	got := walkPaths(t, nil, nil)
+	want = []string{".", "b", "b/y.txt", "b/x", "b/x/z.txt", "a.txt", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestFiles_Walk_prefetch(t *testing.T) {
+	setup()
+	defer teardown()
+	var paths []string
+	for i := 0; i < 10; i++ {
+		paths = append(paths, fmt.Sprintf("/d%d/", i))
+	}
+	tree := newFakeTree(paths...)
+	tree.register(mux)
+
+	// with a concurrency of 2, only the folder after the one being walked
+	// is listed ahead
+	visited := 0
+	walkPaths(t, &WalkOptions{Concurrency: 2}, func(p string, file File, err error) error {
+		if p == "." {
+			return nil
+		}
+		visited++
+		time.Sleep(5 * time.Millisecond)
+		if n, limit := tree.count("list"), visited+2; n > limit {
+			t.Errorf("%s: got: %v listings, want at most %v", p, n, limit)
+		}
+		return nil
+	})
+	if n := tree.count("list"); n != 11 {
+		t.Errorf("got: %v listings, want: 11", n)
+	}
+}
+
+func TestFiles_Walk_skip(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree("/a/1.txt", "/a/2.txt", "/a/3.txt", "/b/x/", "/c.txt")
+	tree.register(mux)
+	opts := &WalkOptions{Concurrency: 1, Sorted: true}
+
+	got := walkPaths(t, opts, func(p string, file File, err error) error {
+		if p == "b" {
+			return SkipDir
+		}
+		return nil
+	})
+	want := []string{".", "a", "a/1.txt", "a/2.txt", "a/3.txt", "b", "c.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	// the skipped folder is never listed without concurrency
+	if n := tree.count("list"); n != 2 {
+		t.Errorf("got: %v listings, want: 2", n)
+	}
+
+	got = walkPaths(t, opts, func(p string, file File, err error) error {
+		if p == "a/2.txt" {
+			return SkipDir
+		}
+		return nil
+	})
+	want = []string{".", "a", "a/1.txt", "a/2.txt", "b", "b/x", "c.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	got = walkPaths(t, opts, func(p string, file File, err error) error {
+		if p == "a/1.txt" {
+			return SkipAll
+		}
+		return nil
+	})
+	want = []string{".", "a", "a/1.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestFiles_Walk_error(t *testing.T) {
+	setup()
+	defer teardown()
+	tree := newFakeTree("/a/1.txt", "/b/2.txt")
+	tree.register(mux)
+	mux.HandleFunc("/v2/files/list", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("parent_id") == "1" {
+			http.Error(w, `{"error_type":"SERVER_ERROR"}`, http.StatusInternalServerError)
+			return
+		}
+		mux := http.NewServeMux()
+		tree.register(mux)
+		mux.ServeHTTP(w, r)
+	})
+	client.RetryPolicy = nil
+
+	var failed []string
+	err := client.Files.Walk(context.Background(), 0, func(p string, file File, err error) error {
+		if err != nil {
+			failed = append(failed, p)
+			return nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(failed, []string{"a"}) {
+		t.Errorf("got: %v, want: [a]", failed)
+	}
+
+	err = client.Files.Walk(context.Background(), 0, func(p string, file File, err error) error {
+		return err
+	})
+	if !errors.Is(err, ErrServerError) {
+		t.Errorf("got: %v, want: %v", err, ErrServerError)
+	}
+}
@@ -0,0 +1,204 @@
+package putio
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+const (
+	// defaultActiveInterval is used when WatchOptions.ActiveInterval is zero.
+	defaultActiveInterval = 2 * time.Second
+
+	// defaultIdleInterval is used when WatchOptions.IdleInterval is zero.
+	defaultIdleInterval = 30 * time.Second
+)
+
+// TransferEventType is the kind of change reported by a TransferEvent.
+type TransferEventType int
+
+// Transfer event types.
+const (
+	// TransferAdded reports a transfer seen for the first time, including
+	// the transfers that exist when the watch starts.
+	TransferAdded TransferEventType = iota + 1
+	// TransferProgress reports new progress of a transfer whose status is
+	// unchanged.
+	TransferProgress
+	// TransferStatusChanged reports a new status other than completed or
+	// failed.
+	TransferStatusChanged
+	// TransferCompleted reports a transfer that has finished downloading,
+	// and is now completed or seeding.
+	TransferCompleted
+	// TransferErrored reports a transfer that has failed.
+	TransferErrored
+	// TransferRemoved reports a transfer that is no longer listed.
+	TransferRemoved
+)
+
+func (t TransferEventType) String() string {
+	switch t {
+	case TransferAdded:
+		return "added"
+	case TransferProgress:
+		return "progress"
+	case TransferStatusChanged:
+		return "status changed"
+	case TransferCompleted:
+		return "completed"
+	case TransferErrored:
+		return "errored"
+	case TransferRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// TransferEvent is a change of a transfer noticed by Watch.
+type TransferEvent struct {
+	Type TransferEventType
+	// Transfer is the latest snapshot of the transfer. For TransferRemoved,
+	// it is the last snapshot seen.
+	Transfer Transfer
+	// Previous is the snapshot before the change, or nil for TransferAdded.
+	Previous *Transfer
+}
+
+// WatchOptions configure TransfersService.Watch.
+type WatchOptions struct {
+	// ActiveInterval is the time between two polls while a transfer is
+	// not in a terminal status. The default is 2 seconds.
+	ActiveInterval time.Duration
+
+	// IdleInterval is the time between two polls while all transfers are in
+	// a terminal status, and after a failed poll. The default is 30 seconds.
+	IdleInterval time.Duration
+
+	// Buffer is the capacity of the event channel. A full channel delays
+	// the next poll until the receiver catches up.
+	Buffer int
+}
+
+// Watch polls the list of transfers and sends the changes between successive
+// lists on the returned channel. Polls are frequent while a transfer is
+// active and slow down when all are finished.
+//
+// The first list is fetched before Watch returns, and its error, if any, is
+// returned. Later failures are logged and retried after IdleInterval. The
+// channel is closed once ctx is done. Nil opts is equivalent to the zero
+// WatchOptions.
+func (t *TransfersService) Watch(ctx context.Context, opts *WatchOptions) (<-chan TransferEvent, error) {
+	var o WatchOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.ActiveInterval <= 0 {
+		o.ActiveInterval = defaultActiveInterval
+	}
+	if o.IdleInterval <= 0 {
+		o.IdleInterval = defaultIdleInterval
+	}
+
+	transfers, err := t.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan TransferEvent, o.Buffer)
+	go t.watch(ctx, &o, transfers, events)
+	return events, nil
+}
+
+func (t *TransfersService) watch(ctx context.Context, o *WatchOptions, transfers []Transfer, events chan<- TransferEvent) {
+	defer close(events)
+
+	seen := make(map[int64]Transfer)
+	for {
+		interval := o.IdleInterval
+		if transfers != nil {
+			current := make(map[int64]Transfer, len(transfers))
+			for _, tr := range transfers {
+				current[tr.ID] = tr
+				if !tr.Status.IsTerminal() {
+					interval = o.ActiveInterval
+				}
+			}
+			for _, ev := range diffTransfers(seen, transfers, current) {
+				select {
+				case events <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+			seen = current
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		var err error
+		transfers, err = t.List(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.client.logger().WarnContext(ctx, "cannot list transfers", "error", err)
+			transfers = nil
+		}
+	}
+}
+
+// diffTransfers returns the events that turn the previous snapshots into the
+// current ones, in the order of the listing followed by the removals in the
+// order of their IDs.
+func diffTransfers(previous map[int64]Transfer, transfers []Transfer, current map[int64]Transfer) []TransferEvent {
+	var events []TransferEvent
+	for _, tr := range transfers {
+		prev, ok := previous[tr.ID]
+		if !ok {
+			events = append(events, TransferEvent{Type: TransferAdded, Transfer: tr})
+			continue
+		}
+		ev := TransferEvent{Transfer: tr, Previous: &prev}
+		switch {
+		case tr.Status != prev.Status && tr.Status.IsCompleted() && !prev.Status.IsCompleted():
+			ev.Type = TransferCompleted
+		case tr.Status != prev.Status && tr.Status.IsFailed():
+			ev.Type = TransferErrored
+		case tr.Status != prev.Status:
+			ev.Type = TransferStatusChanged
+		case transferProgressed(prev, tr):
+			ev.Type = TransferProgress
+		default:
+			continue
+		}
+		events = append(events, ev)
+	}
+	var removed []int64
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i] < removed[j] })
+	for _, id := range removed {
+		prev := previous[id]
+		events = append(events, TransferEvent{Type: TransferRemoved, Transfer: prev, Previous: &prev})
+	}
+	return events
+}
+
+// transferProgressed reports whether the counters of a transfer changed.
+func transferProgressed(prev, tr Transfer) bool {
+	return prev.PercentDone != tr.PercentDone ||
+		prev.Downloaded != tr.Downloaded ||
+		prev.Uploaded != tr.Uploaded ||
+		prev.DownloadSpeed != tr.DownloadSpeed ||
+		prev.UploadSpeed != tr.UploadSpeed ||
+		prev.EstimatedTime != tr.EstimatedTime
+}
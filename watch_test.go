@@ -0,0 +1,131 @@
+package putio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// serveTransfers serves the given lists of transfers in turn, repeating the
+// last one. A nil list fails the request.
+func serveTransfers(lists ...[]Transfer) func() int {
+	var mu sync.Mutex
+	var polls int
+	mux.HandleFunc("/v2/transfers/list", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		list := lists[len(lists)-1]
+		if polls < len(lists) {
+			list = lists[polls]
+		}
+		polls++
+		if list == nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"transfers": list})
+	})
+	return func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return polls
+	}
+}
+
+func TestTransfers_Watch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveTransfers(
+		[]Transfer{{ID: 1, Status: TransferStatusDownloading, PercentDone: 10}, {ID: 2, Status: TransferStatusInQueue}},
+		[]Transfer{{ID: 1, Status: TransferStatusDownloading, PercentDone: 50}, {ID: 2, Status: TransferStatusInQueue}},
+		nil,
+		[]Transfer{{ID: 1, Status: TransferStatusCompleted, PercentDone: 100}, {ID: 2, Status: TransferStatusDownloading}},
+		[]Transfer{{ID: 2, Status: TransferStatusError}, {ID: 3, Status: TransferStatusSeeding}},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.Transfers.Watch(ctx, &WatchOptions{ActiveInterval: time.Millisecond, IdleInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type event struct {
+		Type TransferEventType
+		ID   int64
+	}
+	want := []event{
+		{TransferAdded, 1},
+		{TransferAdded, 2},
+		{TransferProgress, 1},
+		{TransferCompleted, 1},
+		{TransferStatusChanged, 2},
+		{TransferErrored, 2},
+		{TransferAdded, 3},
+		{TransferRemoved, 1},
+	}
+	var got []event
+	for ev := range events {
+		got = append(got, event{ev.Type, ev.Transfer.ID})
+		if ev.Type != TransferAdded && ev.Previous == nil {
+			t.Errorf("%v event of %v has no previous snapshot", ev.Type, ev.Transfer.ID)
+		}
+		if ev.Type == TransferProgress && (ev.Previous.PercentDone != 10 || ev.Transfer.PercentDone != 50) {
+			t.Errorf("got progress from %v to %v", ev.Previous.PercentDone, ev.Transfer.PercentDone)
+		}
+		if len(got) == len(want) {
+			cancel()
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestTransfers_Watch_idle(t *testing.T) {
+	setup()
+	defer teardown()
+
+	polls := serveTransfers([]Transfer{{ID: 1, Status: TransferStatusCompleted}})
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.Transfers.Watch(ctx, &WatchOptions{ActiveInterval: time.Millisecond, IdleInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev := <-events; ev.Type != TransferAdded {
+		t.Errorf("got: %v, want: %v", ev.Type, TransferAdded)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := polls(); n != 1 {
+		t.Errorf("got: %v polls, want: 1", n)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("got an event after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Error("channel is not closed")
+	}
+}
+
+func TestTransfers_Watch_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/transfers/list", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	_, err := client.Transfers.Watch(context.Background(), nil)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("got: %v, want: %v", err, ErrUnauthorized)
+	}
+}
@@ -14,6 +14,9 @@ type ZipsService struct {
 
 // Get gives detailed information about the given zip file id.
 func (z *ZipsService) Get(ctx context.Context, id int64) (Zip, error) {
+	ctx, span := z.client.startSpan(ctx, "ZipsService.Get", attrZipID.Int64(id))
+	defer span.End()
+
 	req, err := z.client.NewRequest(ctx, http.MethodGet, "/v2/zips/"+itoa(id), nil)
 	if err != nil {
 		return Zip{}, err
@@ -30,6 +33,9 @@ func (z *ZipsService) Get(ctx context.Context, id int64) (Zip, error) {
 
 // List lists active zip files.
 func (z *ZipsService) List(ctx context.Context) ([]Zip, error) {
+	ctx, span := z.client.startSpan(ctx, "ZipsService.List")
+	defer span.End()
+
 	req, err := z.client.NewRequest(ctx, http.MethodGet, "/v2/zips/list", nil)
 	if err != nil {
 		return nil, err
@@ -49,6 +55,9 @@ func (z *ZipsService) List(ctx context.Context) ([]Zip, error) {
 // Create creates zip files for given file IDs. If the operation is successful,
 // a zip ID will be returned to keep track of zip process.
 func (z *ZipsService) Create(ctx context.Context, fileIDs ...int64) (int64, error) {
+	ctx, span := z.client.startSpan(ctx, "ZipsService.Create", attrFileIDs.Int64Slice(fileIDs))
+	defer span.End()
+
 	if len(fileIDs) == 0 {
 		return 0, ErrNoFileIDIsGiven
 	}